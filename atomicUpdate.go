@@ -0,0 +1,114 @@
+package solr
+
+import "context"
+
+// AtomicUpdate represents an atomic (partial) update of a single
+// document in Solr. It allows changing the value of some fields
+// without sending the whole document. For example:
+//
+// 	update := solr.NewAtomicUpdate("123").
+// 		Set("title", "new title").
+// 		Add("subjects", "Geography").
+// 		Inc("views", 1)
+// 	err := s.PostAtomic(update)
+type AtomicUpdate struct {
+	Id      string
+	Version int64 // When not zero Solr enforces it (see UpdateIfVersion)
+	fields  map[string]map[string]interface{}
+}
+
+// NewAtomicUpdate creates an atomic update for the document with
+// the id provided.
+func NewAtomicUpdate(id string) *AtomicUpdate {
+	return &AtomicUpdate{Id: id, fields: map[string]map[string]interface{}{}}
+}
+
+// IfVersion makes Solr apply the update only if the _version_ of the
+// document matches (see UpdateIfVersion for the possible values.)
+func (u *AtomicUpdate) IfVersion(version int64) *AtomicUpdate {
+	u.Version = version
+	return u
+}
+
+// Set replaces the value of a field. Use a nil value to remove
+// the field from the document.
+func (u *AtomicUpdate) Set(field string, value interface{}) *AtomicUpdate {
+	return u.op(field, "set", value)
+}
+
+// Add adds value(s) to a multi-value field.
+func (u *AtomicUpdate) Add(field string, value interface{}) *AtomicUpdate {
+	return u.op(field, "add", value)
+}
+
+// AddDistinct adds value(s) to a multi-value field only if they are
+// not already present.
+func (u *AtomicUpdate) AddDistinct(field string, value interface{}) *AtomicUpdate {
+	return u.op(field, "add-distinct", value)
+}
+
+// Remove removes all the occurrences of the value(s) from a
+// multi-value field.
+func (u *AtomicUpdate) Remove(field string, value interface{}) *AtomicUpdate {
+	return u.op(field, "remove", value)
+}
+
+// RemoveRegex removes all the values that match the regular
+// expression(s) from a multi-value field.
+func (u *AtomicUpdate) RemoveRegex(field string, regex interface{}) *AtomicUpdate {
+	return u.op(field, "removeregex", regex)
+}
+
+// Inc increments a numeric field by the amount indicated (use a
+// negative amount to decrement it.)
+func (u *AtomicUpdate) Inc(field string, amount interface{}) *AtomicUpdate {
+	return u.op(field, "inc", amount)
+}
+
+func (u *AtomicUpdate) op(field, operation string, value interface{}) *AtomicUpdate {
+	if u.fields[field] == nil {
+		u.fields[field] = map[string]interface{}{}
+	}
+	u.fields[field][operation] = value
+	return u
+}
+
+// Data returns the update as it is sent to Solr, for example:
+// {"id": "123", "title": {"set": "new title"}}
+func (u *AtomicUpdate) Data() map[string]interface{} {
+	data := map[string]interface{}{"id": u.Id}
+	if u.Version != 0 {
+		data[versionField] = u.Version
+	}
+	for field, ops := range u.fields {
+		data[field] = ops
+	}
+	return data
+}
+
+// PostAtomic sends one or more atomic updates to Solr.
+func (s Solr) PostAtomic(updates ...*AtomicUpdate) error {
+	return s.PostAtomicContext(context.Background(), updates...)
+}
+
+// PostAtomicContext is like PostAtomic but uses the provided context
+// for the HTTP request to Solr.
+func (s Solr) PostAtomicContext(ctx context.Context, updates ...*AtomicUpdate) error {
+	return s.PostAtomicWithOptionsContext(ctx, s.updateOptions(), updates...)
+}
+
+// PostAtomicWithOptions is like PostAtomic but uses the provided
+// update options.
+func (s Solr) PostAtomicWithOptions(opts UpdateOptions, updates ...*AtomicUpdate) error {
+	return s.PostAtomicWithOptionsContext(context.Background(), opts, updates...)
+}
+
+// PostAtomicWithOptionsContext is like PostAtomicWithOptions but uses
+// the provided context for the HTTP request to Solr.
+func (s Solr) PostAtomicWithOptionsContext(ctx context.Context, opts UpdateOptions, updates ...*AtomicUpdate) error {
+	data := []map[string]interface{}{}
+	for _, update := range updates {
+		data = append(data, update.Data())
+	}
+	return s.PostWithOptionsContext(ctx, data, opts)
+}
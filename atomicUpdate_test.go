@@ -0,0 +1,20 @@
+package solr
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestAtomicUpdate(t *testing.T) {
+	update := NewAtomicUpdate("123").
+		Set("title", "new title").
+		Add("subjects", []string{"a", "b"}).
+		Remove("subjects", "c").
+		Inc("views", 1)
+
+	bytes, _ := json.Marshal(update.Data())
+	expected := `{"id":"123","subjects":{"add":["a","b"],"remove":"c"},"title":{"set":"new title"},"views":{"inc":1}}`
+	if string(bytes) != expected {
+		t.Errorf("Unexpected atomic update: %s", bytes)
+	}
+}
@@ -0,0 +1,101 @@
+package solr
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Credentials decorates each outgoing HTTP request to Solr with
+// authentication information (e.g. an Authorization header).
+type Credentials interface {
+	Apply(req *http.Request) error
+}
+
+// BasicAuth provides credentials for Solr's BasicAuthPlugin.
+type BasicAuth struct {
+	Username string
+	Password string
+}
+
+// Apply sets the HTTP Basic authentication header on the request.
+func (c BasicAuth) Apply(req *http.Request) error {
+	req.SetBasicAuth(c.Username, c.Password)
+	return nil
+}
+
+// BearerToken provides a static token (e.g. a JWT for Solr's
+// JWTAuthPlugin) sent as "Authorization: Bearer <token>".
+type BearerToken string
+
+// Apply sets the Bearer authorization header on the request.
+func (t BearerToken) Apply(req *http.Request) error {
+	req.Header.Set("Authorization", "Bearer "+string(t))
+	return nil
+}
+
+// TokenFunc fetches a new token and returns it along with the time
+// at which it expires. A zero expiry means the token never expires.
+type TokenFunc func(ctx context.Context) (token string, expiry time.Time, err error)
+
+// RefreshingToken provides bearer token credentials for short-lived
+// tokens (e.g. JWTs). The token is cached and fetched again via the
+// TokenFunc once it is about to expire. It is safe for concurrent use.
+type RefreshingToken struct {
+	fetch  TokenFunc
+	leeway time.Duration
+
+	mu     sync.Mutex
+	token  string
+	expiry time.Time
+}
+
+// NewRefreshingToken creates a RefreshingToken that calls fetch to
+// get a new token. Tokens are refreshed leeway before they expire.
+func NewRefreshingToken(fetch TokenFunc, leeway time.Duration) *RefreshingToken {
+	return &RefreshingToken{fetch: fetch, leeway: leeway}
+}
+
+// Token returns the current token, fetching a new one if needed.
+func (t *RefreshingToken) Token() (string, error) {
+	return t.TokenContext(context.Background())
+}
+
+// TokenContext is like Token but passes the provided context to the
+// TokenFunc when a new token must be fetched.
+func (t *RefreshingToken) TokenContext(ctx context.Context) (string, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.token != "" && (t.expiry.IsZero() || time.Now().Add(t.leeway).Before(t.expiry)) {
+		return t.token, nil
+	}
+
+	token, expiry, err := t.fetch(ctx)
+	if err != nil {
+		return "", err
+	}
+	t.token = token
+	t.expiry = expiry
+	return token, nil
+}
+
+// Apply sets the Bearer authorization header on the request with
+// the current token.
+func (t *RefreshingToken) Apply(req *http.Request) error {
+	token, err := t.TokenContext(req.Context())
+	if err != nil {
+		return err
+	}
+	return BearerToken(token).Apply(req)
+}
+
+// HeaderFunc adapts an ordinary function to the Credentials interface.
+// Use it to provide custom authentication headers.
+type HeaderFunc func(req *http.Request) error
+
+// Apply calls f(req).
+func (f HeaderFunc) Apply(req *http.Request) error {
+	return f(req)
+}
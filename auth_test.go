@@ -0,0 +1,48 @@
+package solr
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestBasicAuth(t *testing.T) {
+	req, _ := http.NewRequest("GET", "http://localhost", nil)
+	BasicAuth{Username: "solr", Password: "secret"}.Apply(req)
+	user, password, ok := req.BasicAuth()
+	if !ok || user != "solr" || password != "secret" {
+		t.Errorf("Unexpected basic auth: %s/%s", user, password)
+	}
+}
+
+func TestRefreshingToken(t *testing.T) {
+	calls := 0
+	fetch := func(ctx context.Context) (string, time.Time, error) {
+		calls++
+		if calls == 1 {
+			// already expired
+			return "t1", time.Now().Add(-time.Minute), nil
+		}
+		return "t2", time.Now().Add(time.Hour), nil
+	}
+	token := NewRefreshingToken(fetch, time.Second)
+
+	for i := 0; i < 3; i++ {
+		req, _ := http.NewRequest("GET", "http://localhost", nil)
+		if err := token.Apply(req); err != nil {
+			t.Errorf("Apply error: %s", err)
+		}
+		if i > 0 && req.Header.Get("Authorization") != "Bearer t2" {
+			t.Errorf("Unexpected Authorization: %s", req.Header.Get("Authorization"))
+		}
+	}
+
+	if calls != 2 {
+		t.Errorf("Unexpected number of token fetches: %d", calls)
+	}
+
+	if value, err := token.Token(); err != nil || value != "t2" || calls != 2 {
+		t.Errorf("Unexpected token: %s %v", value, err)
+	}
+}
@@ -0,0 +1,304 @@
+package solr
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// BulkIndexerConfig represents the settings of a BulkIndexer.
+// Zero values are replaced with sensible defaults.
+type BulkIndexerConfig struct {
+	Workers       int           // Number of concurrent HTTP requests to Solr (default 2)
+	FlushDocs     int           // Flush when a batch reaches this many documents (default 500)
+	FlushBytes    int           // Flush when a batch reaches this many bytes (default 5MB)
+	FlushInterval time.Duration // Flush a partial batch after this long (default 30s)
+	MaxRetries    int           // Retries for transient failures (default 3, use -1 for none)
+	RetryBackoff  time.Duration // Wait before the first retry, doubled on each retry (default 1s)
+
+	// UpdateOptions used for each batch. The zero value (no commit) is
+	// usually what you want since Close() commits once at the end.
+	UpdateOptions UpdateOptions
+
+	// OnBatch (optional) is called after each batch has been sent to
+	// Solr. It is called from the worker goroutines.
+	OnBatch func(BatchResult)
+}
+
+// BatchResult reports the outcome of sending one batch to Solr.
+type BatchResult struct {
+	Docs     int   // Number of documents in the batch
+	Bytes    int   // Size of the batch in bytes
+	Attempts int   // Number of HTTP requests issued for the batch
+	Err      error // nil if the batch was indexed
+}
+
+// BulkIndexerStats reports the totals of a BulkIndexer.
+type BulkIndexerStats struct {
+	Added   int // Documents added via Add()
+	Indexed int // Documents indexed successfully
+	Failed  int // Documents in batches that failed
+}
+
+// BulkIndexer sends documents to Solr in batches using a pool of
+// workers. Documents are added via Add() (which is safe to call from
+// many goroutines) and Close() must be called at the end to flush the
+// pending documents and commit them.
+//
+// Add() blocks when all the workers are busy and a batch is already
+// waiting to be sent (i.e. it provides back-pressure.)
+type BulkIndexer struct {
+	solr   Solr
+	config BulkIndexerConfig
+	ctx    context.Context
+
+	mu      sync.Mutex
+	docs    []json.RawMessage
+	bytes   int
+	closed  bool
+	stats   BulkIndexerStats
+	lastErr error
+
+	batches chan []json.RawMessage
+	sending sync.WaitGroup // batches taken but not yet on the channel
+	workers sync.WaitGroup
+	done    chan struct{}
+	ticker  *time.Ticker
+}
+
+// NewBulkIndexer creates a BulkIndexer that sends documents to this
+// instance of Solr.
+func (s Solr) NewBulkIndexer(config BulkIndexerConfig) *BulkIndexer {
+	return s.NewBulkIndexerContext(context.Background(), config)
+}
+
+// NewBulkIndexerContext is like NewBulkIndexer but uses the provided
+// context for all the HTTP requests issued by the indexer.
+func (s Solr) NewBulkIndexerContext(ctx context.Context, config BulkIndexerConfig) *BulkIndexer {
+	config.setDefaults()
+	bi := &BulkIndexer{
+		solr:    s,
+		config:  config,
+		ctx:     ctx,
+		batches: make(chan []json.RawMessage, config.Workers),
+		done:    make(chan struct{}),
+		ticker:  time.NewTicker(config.FlushInterval),
+	}
+
+	for i := 0; i < config.Workers; i++ {
+		bi.workers.Add(1)
+		go bi.worker()
+	}
+	go bi.flusher()
+	return bi
+}
+
+func (config *BulkIndexerConfig) setDefaults() {
+	if config.Workers <= 0 {
+		config.Workers = 2
+	}
+	if config.FlushDocs <= 0 {
+		config.FlushDocs = 500
+	}
+	if config.FlushBytes <= 0 {
+		config.FlushBytes = 5 * 1024 * 1024
+	}
+	if config.FlushInterval <= 0 {
+		config.FlushInterval = 30 * time.Second
+	}
+	if config.MaxRetries == 0 {
+		config.MaxRetries = 3
+	}
+	if config.RetryBackoff <= 0 {
+		config.RetryBackoff = time.Second
+	}
+}
+
+// Add queues a document to be sent to Solr.
+func (bi *BulkIndexer) Add(doc Document) error {
+	return bi.AddOne(doc.solrData())
+}
+
+// AddOne queues a document to be sent to Solr. The map key represents
+// the field name and the map value the field value.
+func (bi *BulkIndexer) AddOne(datum map[string]interface{}) error {
+	bytes, err := json.Marshal(datum)
+	if err != nil {
+		return err
+	}
+
+	bi.mu.Lock()
+	if bi.closed {
+		bi.mu.Unlock()
+		return errors.New("BulkIndexer is closed")
+	}
+	bi.docs = append(bi.docs, bytes)
+	bi.bytes += len(bytes)
+	bi.stats.Added++
+	var batch []json.RawMessage
+	if len(bi.docs) >= bi.config.FlushDocs || bi.bytes >= bi.config.FlushBytes {
+		batch = bi.takeBatch()
+	}
+	bi.mu.Unlock()
+
+	return bi.send(batch)
+}
+
+// Flush sends the documents queued so far to Solr without waiting
+// for the batch to be full.
+func (bi *BulkIndexer) Flush() error {
+	bi.mu.Lock()
+	batch := bi.takeBatch()
+	bi.mu.Unlock()
+	return bi.send(batch)
+}
+
+// Close flushes the pending documents, waits for all the batches to
+// be sent, and issues a single commit to Solr. It returns the error
+// of the last batch that failed (if any) or the error from the commit.
+func (bi *BulkIndexer) Close() error {
+	bi.mu.Lock()
+	if bi.closed {
+		bi.mu.Unlock()
+		return errors.New("BulkIndexer is already closed")
+	}
+	bi.closed = true
+	batch := bi.takeBatch()
+	bi.mu.Unlock()
+
+	bi.ticker.Stop()
+	close(bi.done)
+	err := bi.send(batch)
+	bi.sending.Wait()
+	close(bi.batches)
+	bi.workers.Wait()
+
+	if err == nil {
+		err = bi.solr.CommitContext(bi.ctx)
+	}
+
+	bi.mu.Lock()
+	defer bi.mu.Unlock()
+	if bi.lastErr != nil {
+		return bi.lastErr
+	}
+	return err
+}
+
+// Stats returns the totals for the documents processed so far.
+func (bi *BulkIndexer) Stats() BulkIndexerStats {
+	bi.mu.Lock()
+	defer bi.mu.Unlock()
+	return bi.stats
+}
+
+// takeBatch returns the documents queued so far and resets the
+// queue. Must be called with the mutex held and the batch returned
+// must be passed to send().
+func (bi *BulkIndexer) takeBatch() []json.RawMessage {
+	batch := bi.docs
+	bi.docs = nil
+	bi.bytes = 0
+	if len(batch) > 0 {
+		bi.sending.Add(1)
+	}
+	return batch
+}
+
+func (bi *BulkIndexer) send(batch []json.RawMessage) error {
+	if len(batch) == 0 {
+		return nil
+	}
+	defer bi.sending.Done()
+	select {
+	case bi.batches <- batch:
+		return nil
+	case <-bi.ctx.Done():
+		bi.mu.Lock()
+		bi.stats.Failed += len(batch)
+		bi.mu.Unlock()
+		return bi.ctx.Err()
+	}
+}
+
+func (bi *BulkIndexer) flusher() {
+	for {
+		select {
+		case <-bi.ticker.C:
+			bi.mu.Lock()
+			var batch []json.RawMessage
+			if !bi.closed {
+				batch = bi.takeBatch()
+			}
+			bi.mu.Unlock()
+			bi.send(batch)
+		case <-bi.done:
+			return
+		}
+	}
+}
+
+func (bi *BulkIndexer) worker() {
+	defer bi.workers.Done()
+	for batch := range bi.batches {
+		result := bi.post(batch)
+
+		bi.mu.Lock()
+		if result.Err == nil {
+			bi.stats.Indexed += result.Docs
+		} else {
+			bi.stats.Failed += result.Docs
+			bi.lastErr = result.Err
+		}
+		bi.mu.Unlock()
+
+		if bi.config.OnBatch != nil {
+			bi.config.OnBatch(result)
+		}
+	}
+}
+
+// post sends a batch to Solr retrying transient failures.
+func (bi *BulkIndexer) post(batch []json.RawMessage) BatchResult {
+	docs := make([]string, len(batch))
+	for i, doc := range batch {
+		docs[i] = string(doc)
+	}
+	data := "[" + strings.Join(docs, ",") + "]"
+	result := BatchResult{Docs: len(batch), Bytes: len(data)}
+
+	backoff := bi.config.RetryBackoff
+	for {
+		result.Attempts++
+		result.Err = bi.solr.PostStringWithOptionsContext(bi.ctx, data, bi.config.UpdateOptions)
+		if result.Err == nil || !isTransient(result.Err) || result.Attempts > bi.config.MaxRetries {
+			return result
+		}
+
+		bi.solr.log("Solr bulk indexer retrying batch", result.Err.Error())
+		select {
+		case <-time.After(backoff):
+			backoff *= 2
+		case <-bi.ctx.Done():
+			result.Err = bi.ctx.Err()
+			return result
+		}
+	}
+}
+
+// isTransient returns true if the error might go away if the
+// request is retried (e.g. network errors or Solr being unavailable).
+func isTransient(err error) bool {
+	if err == context.Canceled || err == context.DeadlineExceeded {
+		return false
+	}
+	if e, ok := asSolrError(err); ok {
+		status := e.status()
+		return status >= 500 || status == http.StatusTooManyRequests
+	}
+	return true
+}
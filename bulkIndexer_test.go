@@ -0,0 +1,85 @@
+package solr
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestBulkIndexer(t *testing.T) {
+	var mu sync.Mutex
+	docs, commits, requests := 0, 0, 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		requests++
+		if requests == 1 {
+			// make sure transient errors are retried
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		body, _ := ioutil.ReadAll(r.Body)
+		if strings.HasPrefix(string(body), `{"commit"`) {
+			commits++
+		} else {
+			var data []map[string]interface{}
+			if err := json.Unmarshal(body, &data); err != nil {
+				t.Errorf("Invalid batch: %s", err)
+			}
+			docs += len(data)
+		}
+		w.Write([]byte(`{"responseHeader":{"status":0}}`))
+	}))
+	defer server.Close()
+
+	batches := 0
+	config := BulkIndexerConfig{
+		Workers:      3,
+		FlushDocs:    10,
+		RetryBackoff: time.Millisecond,
+		OnBatch: func(result BatchResult) {
+			mu.Lock()
+			defer mu.Unlock()
+			batches++
+			if result.Err != nil {
+				t.Errorf("Batch error: %s", result.Err)
+			}
+		},
+	}
+	solr := New(server.URL, false)
+	bi := solr.NewBulkIndexer(config)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 21; j++ {
+				doc := map[string]interface{}{"id": fmt.Sprintf("%d-%d", i, j)}
+				if err := bi.AddOne(doc); err != nil {
+					t.Errorf("Add error: %s", err)
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	if err := bi.Close(); err != nil {
+		t.Errorf("Close error: %s", err)
+	}
+
+	stats := bi.Stats()
+	if docs != 105 || stats.Added != 105 || stats.Indexed != 105 || stats.Failed != 0 {
+		t.Errorf("Unexpected number of documents: %d %#v", docs, stats)
+	}
+	if batches != 11 || commits != 1 {
+		t.Errorf("Unexpected number of batches/commits: %d/%d", batches, commits)
+	}
+}
@@ -0,0 +1,132 @@
+package solr
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hectorcorrea/solr/query"
+)
+
+const childDocumentsField = "_childDocuments_"
+
+// AddChild adds an anonymous child document (i.e. a document in the
+// same parent/child block that is not associated with a field.)
+func (d *Document) AddChild(child Document) {
+	d.Children = append(d.Children, child)
+}
+
+// AddNested adds a child document in a labelled field (e.g. a
+// "comments" field in a blog post.)
+func (d *Document) AddNested(field string, child Document) {
+	if d.Nested == nil {
+		d.Nested = map[string][]Document{}
+	}
+	d.Nested[field] = append(d.Nested[field], child)
+}
+
+// HasChildren returns true if the document has child documents
+// (either anonymous or in labelled fields.)
+func (d Document) HasChildren() bool {
+	return len(d.Children) > 0 || len(d.Nested) > 0
+}
+
+// solrData returns the data of the document as it must be sent to
+// Solr, i.e. including the child documents.
+func (d Document) solrData() map[string]interface{} {
+	if !d.HasChildren() {
+		return d.Data
+	}
+
+	data := map[string]interface{}{}
+	for field, value := range d.Data {
+		data[field] = value
+	}
+	if len(d.Children) > 0 {
+		data[childDocumentsField] = childrenData(d.Children)
+	}
+	for field, children := range d.Nested {
+		data[field] = childrenData(children)
+	}
+	return data
+}
+
+func childrenData(children []Document) []map[string]interface{} {
+	data := []map[string]interface{}{}
+	for _, child := range children {
+		data = append(data, child.solrData())
+	}
+	return data
+}
+
+// childDocuments returns the child documents in a value returned by
+// Solr. Returns false if the value is not a (list of) document(s).
+// Objects in fields other than _childDocuments_ (e.g. the output of
+// [explain style=nl] or [subquery]) are only considered documents
+// when they include the unique key.
+func childDocuments(field string, value interface{}) ([]Document, bool) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if !isChildDocument(field, v) {
+			return nil, false
+		}
+		return []Document{newDocumentFromSolrDoc(v)}, true
+	case []interface{}:
+		if len(v) == 0 {
+			return nil, false
+		}
+		docs := []Document{}
+		for _, item := range v {
+			data, ok := item.(map[string]interface{})
+			if !ok || !isChildDocument(field, data) {
+				return nil, false
+			}
+			docs = append(docs, newDocumentFromSolrDoc(data))
+		}
+		return docs, true
+	}
+	return nil, false
+}
+
+func isChildDocument(field string, data map[string]interface{}) bool {
+	if field == childDocumentsField {
+		return true
+	}
+	_, ok := data["id"]
+	return ok
+}
+
+// ParentQuery returns a block join query that matches the parent
+// documents (identified by the parentFilter, e.g. "type:book") of the
+// children that match childQuery, i.e. {!parent which="..."}childQuery
+func ParentQuery(parentFilter, childQuery string) string {
+	return query.LocalParams("parent", query.Raw(childQuery)).With("which", parentFilter).String()
+}
+
+// ChildQuery returns a block join query that matches the children
+// of the parent documents (identified by the parentFilter, e.g.
+// "type:book") that match parentQuery, i.e. {!child of="..."}parentQuery
+func ChildQuery(parentFilter, parentQuery string) string {
+	return query.LocalParams("child", query.Raw(parentQuery)).With("of", parentFilter).String()
+}
+
+// ChildTransformer returns the [child] doc transformer to include in
+// the fl parameter to fetch the child documents along with their parents.
+// parentFilter can be empty when using a nested schema (Solr 8+),
+// childFilter can be empty to return all children, and limit can
+// be zero to use Solr's default.
+func ChildTransformer(parentFilter, childFilter string, limit int) string {
+	params := []string{}
+	if parentFilter != "" {
+		params = append(params, "parentFilter="+query.LocalParamValue(parentFilter))
+	}
+	if childFilter != "" {
+		params = append(params, "childFilter="+query.LocalParamValue(childFilter))
+	}
+	if limit != 0 {
+		params = append(params, fmt.Sprintf("limit=%d", limit))
+	}
+	if len(params) == 0 {
+		return "[child]"
+	}
+	return "[child " + strings.Join(params, " ") + "]"
+}
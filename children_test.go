@@ -0,0 +1,69 @@
+package solr
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestChildrenEncode(t *testing.T) {
+	parent := NewDocument()
+	parent.Data["id"] = "1"
+	child := NewDocument()
+	child.Data["id"] = "1.1"
+	parent.AddChild(child)
+	parent.AddNested("comments", child)
+
+	bytes, _ := json.Marshal(parent.solrData())
+	expected := `{"_childDocuments_":[{"id":"1.1"}],"comments":[{"id":"1.1"}],"id":"1"}`
+	if string(bytes) != expected {
+		t.Errorf("Unexpected child documents: %s", bytes)
+	}
+
+	if _, ok := parent.Data[childDocumentsField]; ok {
+		t.Errorf("Document data was modified: %v", parent.Data)
+	}
+}
+
+func TestChildrenDecode(t *testing.T) {
+	raw, err := NewResponseRaw([]byte(`{"response":{"numFound":1,"start":0,"docs":[{
+		"id":"1",
+		"tags":["a","b"],
+		"_childDocuments_":[{"id":"1.1"},{"id":"1.2"}],
+		"comments":[{"id":"1.3","replies":{"id":"1.3.1"}}],
+		"[explain]":{"match":true,"value":1.5,"details":[{"value":1.5}]}}]}}`))
+	if err != nil {
+		t.Fatalf("NewResponseRaw error: %s", err)
+	}
+
+	doc := newDocumentFromSolrResponse(raw)[0]
+	if len(doc.Children) != 2 || doc.Children[1].Id() != "1.2" {
+		t.Errorf("Unexpected children: %v", doc.Children)
+	}
+
+	comments := doc.Nested["comments"]
+	if len(comments) != 1 || comments[0].Nested["replies"][0].Id() != "1.3.1" {
+		t.Errorf("Unexpected nested children: %v", doc.Nested)
+	}
+
+	if _, ok := doc.Nested["[explain]"]; ok {
+		t.Errorf("Unexpected nested children: %v", doc.Nested)
+	}
+
+	if len(doc.Values("tags")) != 2 || doc.Data["comments"] != nil || doc.Data["[explain]"] == nil {
+		t.Errorf("Unexpected data: %v", doc.Data)
+	}
+}
+
+func TestBlockJoinQueries(t *testing.T) {
+	if q := ParentQuery("type:book", "comment:great"); q != `{!parent which="type:book"}comment:great` {
+		t.Errorf("Unexpected parent query: %s", q)
+	}
+
+	if q := ChildQuery(`title:"a b"`, "id:1"); q != `{!child of="title:\"a b\""}id:1` {
+		t.Errorf("Unexpected child query: %s", q)
+	}
+
+	if fl := ChildTransformer("type:book", "", 10); fl != `[child parentFilter="type:book" limit=10]` {
+		t.Errorf("Unexpected child transformer: %s", fl)
+	}
+}
@@ -0,0 +1,121 @@
+package solr
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"time"
+)
+
+// The convertXxx functions convert a single value as it comes from
+// Solr (or as it was set in Document.Data) to a specific Go type.
+
+func convertInt64(value interface{}) (int64, error) {
+	switch v := value.(type) {
+	case int64:
+		return v, nil
+	case int:
+		return int64(v), nil
+	case int32:
+		return int64(v), nil
+	case float64:
+		if v != math.Trunc(v) {
+			return 0, fmt.Errorf("value %v is not an integer", v)
+		}
+		return int64(v), nil
+	case float32:
+		return convertInt64(float64(v))
+	case json.Number:
+		return strconv.ParseInt(string(v), 10, 64)
+	case string:
+		return strconv.ParseInt(v, 10, 64)
+	}
+	return 0, conversionError(value, "int64")
+}
+
+func convertFloat64(value interface{}) (float64, error) {
+	switch v := value.(type) {
+	case float64:
+		return v, nil
+	case float32:
+		return float64(v), nil
+	case int:
+		return float64(v), nil
+	case int64:
+		return float64(v), nil
+	case int32:
+		return float64(v), nil
+	case json.Number:
+		return v.Float64()
+	case string:
+		return strconv.ParseFloat(v, 64)
+	}
+	return 0, conversionError(value, "float64")
+}
+
+func convertBool(value interface{}) (bool, error) {
+	switch v := value.(type) {
+	case bool:
+		return v, nil
+	case string:
+		return strconv.ParseBool(v)
+	}
+	return false, conversionError(value, "bool")
+}
+
+// convertTime parses Solr's dates (ISO-8601 in UTC, for example
+// 2019-01-01T10:00:00Z or 2019-01-01T10:00:00.123Z)
+func convertTime(value interface{}) (time.Time, error) {
+	switch v := value.(type) {
+	case time.Time:
+		return v, nil
+	case string:
+		return time.Parse(time.RFC3339Nano, v)
+	}
+	return time.Time{}, conversionError(value, "time.Time")
+}
+
+func convertString(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case json.Number:
+		return string(v)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case time.Time:
+		return formatTime(v)
+	}
+	return fmt.Sprintf("%v", value)
+}
+
+// formatTime formats a time in the format expected by Solr.
+func formatTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339Nano)
+}
+
+// toSlice returns the values in a multi-value field (mimics an array
+// of one if the field is single value.)
+func toSlice(value interface{}) []interface{} {
+	if value == nil {
+		return nil
+	}
+	if values, ok := value.([]interface{}); ok {
+		return values
+	}
+	dynValue := reflect.ValueOf(value)
+	if dynValue.Kind() != reflect.Slice {
+		return []interface{}{value}
+	}
+	values := make([]interface{}, dynValue.Len())
+	for i := range values {
+		values[i] = dynValue.Index(i).Interface()
+	}
+	return values
+}
+
+func conversionError(value interface{}, typeName string) error {
+	return fmt.Errorf("cannot convert %v (%T) to %s", value, value, typeName)
+}
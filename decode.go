@@ -0,0 +1,301 @@
+package solr
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// Decode copies the values in the document to the struct pointed
+// by v. The fields of the struct are matched to the Solr fields via
+// the `solr` tag, for example:
+//
+// 	type Book struct {
+// 		Id        string     `solr:"id"`
+// 		Title     string     `solr:"title_s"`
+// 		Subjects  []string   `solr:"subjects_ss"`
+// 		Year      *int       `solr:"year_i"`
+// 		Published time.Time  `solr:"published_dt"`
+// 		Ignored   string     `solr:"-"`
+// 	}
+//
+// Fields without a tag are matched by their Go name. Pointer fields
+// are left nil when the document does not have a value for them.
+// Multi-value fields decoded into a non-slice field take the first value.
+func (d Document) Decode(v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return errors.New("Decode requires a non-nil pointer to a struct")
+	}
+	return decodeStruct(d.Data, rv.Elem())
+}
+
+// DecodeAll decodes the documents in the response into the slice
+// of structs pointed by v (e.g. a *[]Book). See Document.Decode()
+func (r SearchResponse) DecodeAll(v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Slice {
+		return errors.New("DecodeAll requires a non-nil pointer to a slice")
+	}
+
+	slice := rv.Elem()
+	elemType := slice.Type().Elem()
+	structType := elemType
+	if elemType.Kind() == reflect.Ptr {
+		structType = elemType.Elem()
+	}
+	if structType.Kind() != reflect.Struct {
+		return errors.New("DecodeAll requires a pointer to a slice of structs")
+	}
+
+	result := reflect.MakeSlice(slice.Type(), 0, len(r.Documents))
+	for _, doc := range r.Documents {
+		item := reflect.New(structType)
+		if err := decodeStruct(doc.Data, item.Elem()); err != nil {
+			return err
+		}
+		if elemType.Kind() != reflect.Ptr {
+			item = item.Elem()
+		}
+		result = reflect.Append(result, item)
+	}
+	slice.Set(result)
+	return nil
+}
+
+// EncodeDocument creates a Document from a struct (or a pointer to
+// a struct) using the same `solr` tags as Document.Decode(). Fields
+// tagged with omitempty are not included when they have the zero value,
+// nil pointers are never included. As with Decode(), fields of other
+// types than scalars, time.Time, and slices of them (e.g. maps or
+// structs) result in an error.
+func EncodeDocument(v interface{}) (Document, error) {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Ptr && !rv.IsNil() {
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return Document{}, errors.New("EncodeDocument requires a struct")
+	}
+
+	doc := NewDocument()
+	for _, field := range structFields(rv.Type()) {
+		value := rv.Field(field.index)
+		if field.omitEmpty && isEmptyValue(value) {
+			continue
+		}
+		encoded, ok, err := encodeValue(value)
+		if err != nil {
+			return Document{}, fmt.Errorf("solr field %s: %s", field.name, err)
+		}
+		if ok {
+			doc.Data[field.name] = encoded
+		}
+	}
+	return doc, nil
+}
+
+type structField struct {
+	index     int
+	name      string
+	omitEmpty bool
+}
+
+// structFields returns the exported fields of the struct type along
+// with the Solr field name and options in their `solr` tag.
+func structFields(t reflect.Type) []structField {
+	fields := []structField{}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.PkgPath != "" {
+			// unexported field
+			continue
+		}
+		tag := f.Tag.Get("solr")
+		if tag == "-" {
+			continue
+		}
+		tokens := strings.Split(tag, ",")
+		field := structField{index: i, name: tokens[0]}
+		if field.name == "" {
+			field.name = f.Name
+		}
+		for _, option := range tokens[1:] {
+			if option == "omitempty" {
+				field.omitEmpty = true
+			}
+		}
+		fields = append(fields, field)
+	}
+	return fields
+}
+
+func decodeStruct(data map[string]interface{}, rv reflect.Value) error {
+	for _, field := range structFields(rv.Type()) {
+		value, ok := data[field.name]
+		if !ok || value == nil {
+			continue
+		}
+		if err := decodeValue(rv.Field(field.index), value); err != nil {
+			return fmt.Errorf("solr field %s: %s", field.name, err)
+		}
+	}
+	return nil
+}
+
+// decodeValue sets dst to the value provided converting it to the
+// type of dst.
+func decodeValue(dst reflect.Value, value interface{}) error {
+	if dst.Type() == timeType {
+		values := toSlice(value)
+		if len(values) == 0 {
+			return nil
+		}
+		t, err := convertTime(values[0])
+		if err != nil {
+			return err
+		}
+		dst.Set(reflect.ValueOf(t))
+		return nil
+	}
+
+	switch dst.Kind() {
+	case reflect.Ptr:
+		elem := reflect.New(dst.Type().Elem())
+		if err := decodeValue(elem.Elem(), value); err != nil {
+			return err
+		}
+		dst.Set(elem)
+		return nil
+	case reflect.Interface:
+		if value == nil {
+			// Leave the zero value (e.g. a null in a multi-valued field)
+			return nil
+		}
+		v := reflect.ValueOf(value)
+		if !v.Type().AssignableTo(dst.Type()) {
+			return fmt.Errorf("cannot assign %T to %s", value, dst.Type())
+		}
+		dst.Set(v)
+		return nil
+	case reflect.Slice:
+		values := toSlice(value)
+		slice := reflect.MakeSlice(dst.Type(), len(values), len(values))
+		for i, v := range values {
+			if err := decodeValue(slice.Index(i), v); err != nil {
+				return err
+			}
+		}
+		dst.Set(slice)
+		return nil
+	}
+
+	// Single value field, use the first value if we got many.
+	values := toSlice(value)
+	if len(values) == 0 {
+		return nil
+	}
+	value = values[0]
+
+	switch dst.Kind() {
+	case reflect.String:
+		dst.SetString(convertString(value))
+	case reflect.Bool:
+		b, err := convertBool(value)
+		if err != nil {
+			return err
+		}
+		dst.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := convertInt64(value)
+		if err != nil {
+			return err
+		}
+		if dst.OverflowInt(i) {
+			return fmt.Errorf("value %d overflows %s", i, dst.Type())
+		}
+		dst.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		i, err := convertInt64(value)
+		if err != nil {
+			return err
+		}
+		if i < 0 || dst.OverflowUint(uint64(i)) {
+			return fmt.Errorf("value %d overflows %s", i, dst.Type())
+		}
+		dst.SetUint(uint64(i))
+	case reflect.Float32, reflect.Float64:
+		f, err := convertFloat64(value)
+		if err != nil {
+			return err
+		}
+		dst.SetFloat(f)
+	default:
+		return fmt.Errorf("unsupported type %s", dst.Type())
+	}
+	return nil
+}
+
+// encodeValue returns the value to send to Solr for a struct field.
+// Returns false if the value should not be sent (e.g. nil pointers).
+// As in decodeValue only scalars, time.Time, and slices of them are
+// supported (e.g. structs and maps would be sent as nested objects.)
+func encodeValue(value reflect.Value) (interface{}, bool, error) {
+	if value.Type() == timeType {
+		return formatTime(value.Interface().(time.Time)), true, nil
+	}
+
+	switch value.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if value.IsNil() {
+			return nil, false, nil
+		}
+		return encodeValue(value.Elem())
+	case reflect.Slice:
+		if value.IsNil() {
+			return nil, false, nil
+		}
+		values := []interface{}{}
+		for i := 0; i < value.Len(); i++ {
+			v, ok, err := encodeValue(value.Index(i))
+			if err != nil {
+				return nil, false, err
+			}
+			if ok {
+				values = append(values, v)
+			}
+		}
+		return values, true, nil
+	case reflect.String, reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return value.Interface(), true, nil
+	}
+	return nil, false, fmt.Errorf("unsupported type %s", value.Type())
+}
+
+func isEmptyValue(v reflect.Value) bool {
+	if v.Type() == timeType {
+		return v.Interface().(time.Time).IsZero()
+	}
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Bool:
+		return !v.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int() == 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return v.Uint() == 0
+	case reflect.Float32, reflect.Float64:
+		return v.Float() == 0
+	case reflect.Interface, reflect.Ptr:
+		return v.IsNil()
+	}
+	return false
+}
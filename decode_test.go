@@ -0,0 +1,130 @@
+package solr
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+)
+
+type testBook struct {
+	Id        string    `solr:"id"`
+	Title     string    `solr:"title_s"`
+	Subjects  []string  `solr:"subjects_ss"`
+	Year      *int      `solr:"year_i"`
+	Pages     int       `solr:"pages_i,omitempty"`
+	Price     float64   `solr:"price_f"`
+	Available bool      `solr:"available_b"`
+	Published time.Time `solr:"published_dt"`
+	Ignored   string    `solr:"-"`
+}
+
+func TestDecode(t *testing.T) {
+	doc := NewDocument()
+	json.Unmarshal([]byte(`{
+		"id": "1",
+		"title_s": ["one"],
+		"subjects_ss": ["a", "b"],
+		"year_i": 1999,
+		"price_f": 10.5,
+		"available_b": true,
+		"published_dt": "1999-01-02T03:04:05Z",
+		"Ignored": "x"
+	}`), &doc.Data)
+
+	var book testBook
+	if err := doc.Decode(&book); err != nil {
+		t.Fatalf("Decode error: %s", err)
+	}
+
+	published := time.Date(1999, 1, 2, 3, 4, 5, 0, time.UTC)
+	if book.Id != "1" || book.Title != "one" || len(book.Subjects) != 2 ||
+		book.Year == nil || *book.Year != 1999 || book.Price != 10.5 ||
+		!book.Available || !book.Published.Equal(published) || book.Ignored != "" {
+		t.Errorf("Unexpected decoded value: %#v", book)
+	}
+
+	doc.Data["year_i"] = "not a number"
+	if err := doc.Decode(&book); err == nil {
+		t.Errorf("Expected a decode error")
+	}
+
+	r := SearchResponse{Documents: []Document{doc, doc}}
+	delete(doc.Data, "year_i")
+	var books []testBook
+	if err := r.DecodeAll(&books); err != nil || len(books) != 2 || books[0].Year != nil {
+		t.Errorf("Unexpected DecodeAll result: %v %v", books, err)
+	}
+}
+
+func TestDecodeInterface(t *testing.T) {
+	doc := NewDocument()
+	doc.Data["title_s"] = "one"
+
+	var plain struct {
+		Title interface{} `solr:"title_s"`
+	}
+	if err := doc.Decode(&plain); err != nil || plain.Title != "one" {
+		t.Errorf("Unexpected decoded value: %#v %v", plain, err)
+	}
+
+	doc.Data["tags_ss"] = []interface{}{"a", nil}
+	var tags struct {
+		Tags []interface{} `solr:"tags_ss"`
+	}
+	if err := doc.Decode(&tags); err != nil || len(tags.Tags) != 2 || tags.Tags[0] != "a" || tags.Tags[1] != nil {
+		t.Errorf("Unexpected decoded value: %#v %v", tags, err)
+	}
+
+	var stringer struct {
+		Title fmt.Stringer `solr:"title_s"`
+	}
+	if err := doc.Decode(&stringer); err == nil {
+		t.Errorf("Expected a decode error for a fmt.Stringer field")
+	}
+}
+
+func TestEncodeDocument(t *testing.T) {
+	book := testBook{
+		Id:        "1",
+		Subjects:  []string{"a"},
+		Published: time.Date(1999, 1, 2, 3, 4, 5, 0, time.UTC),
+	}
+	doc, err := EncodeDocument(&book)
+	if err != nil {
+		t.Fatalf("EncodeDocument error: %s", err)
+	}
+
+	if doc.Data["id"] != "1" || doc.Data["published_dt"] != "1999-01-02T03:04:05Z" {
+		t.Errorf("Unexpected encoded values: %v", doc.Data)
+	}
+
+	if _, ok := doc.Data["pages_i"]; ok {
+		t.Errorf("Unexpected omitempty value: %v", doc.Data)
+	}
+
+	if _, ok := doc.Data["year_i"]; ok {
+		t.Errorf("Unexpected nil value: %v", doc.Data)
+	}
+
+	if _, ok := doc.Data["title_s"]; !ok {
+		t.Errorf("Missing empty value: %v", doc.Data)
+	}
+}
+
+func TestEncodeDocumentUnsupported(t *testing.T) {
+	var withMap struct {
+		Meta map[string]string `solr:"meta"`
+	}
+	withMap.Meta = map[string]string{"a": "b"}
+	if _, err := EncodeDocument(withMap); err == nil {
+		t.Errorf("Expected an error for a map field")
+	}
+
+	var withStruct struct {
+		Book testBook `solr:"book"`
+	}
+	if _, err := EncodeDocument(&withStruct); err == nil {
+		t.Errorf("Expected an error for a struct field")
+	}
+}
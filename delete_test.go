@@ -0,0 +1,55 @@
+package solr
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestDelete(t *testing.T) {
+	var payload map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		payload = nil
+		json.Unmarshal(body, &payload)
+		w.Write([]byte(`{"responseHeader":{"status":0}}`))
+	}))
+	defer server.Close()
+
+	solr := New(server.URL, false)
+	if err := solr.Delete([]string{"a<b&c", "d"}); err != nil {
+		t.Errorf("Delete error: %s", err)
+	}
+	expected := map[string]interface{}{"delete": []interface{}{"a<b&c", "d"}}
+	if !reflect.DeepEqual(payload, expected) {
+		t.Errorf("Unexpected delete payload: %#v", payload)
+	}
+
+	if err := solr.DeleteByQuery("author:\"ada\""); err != nil {
+		t.Errorf("DeleteByQuery error: %s", err)
+	}
+	expected = map[string]interface{}{"delete": map[string]interface{}{"query": "author:\"ada\""}}
+	if !reflect.DeepEqual(payload, expected) {
+		t.Errorf("Unexpected delete payload: %#v", payload)
+	}
+
+	if err := solr.DeleteByQuery(""); err != ErrEmptyQuery {
+		t.Errorf("Expected ErrEmptyQuery, got: %v", err)
+	}
+
+	deletes := []DeleteId{{Id: "1", Version: 5}, {Id: "2", Route: "shard1"}}
+	if err := solr.DeleteDocsContext(context.Background(), deletes); err != nil {
+		t.Errorf("DeleteDocs error: %s", err)
+	}
+	expected = map[string]interface{}{"delete": []interface{}{
+		map[string]interface{}{"id": "1", "_version_": float64(5)},
+		map[string]interface{}{"id": "2", "_route_": "shard1"},
+	}}
+	if !reflect.DeepEqual(payload, expected) {
+		t.Errorf("Unexpected delete payload: %#v", payload)
+	}
+}
@@ -1,11 +1,28 @@
 package solr
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
-	"reflect"
+	"strconv"
 	"strings"
+	"time"
 )
 
+const versionField = "_version_"
+
+// ErrNoValue is returned by the ValueXxxErr() methods when the
+// document does not have a value for the field.
+var ErrNoValue = errors.New("field has no value")
+
+// fieldError wraps err with the name of the field.
+func fieldError(fieldName string, err error) error {
+	if err == nil {
+		return nil
+	}
+	return fmt.Errorf("field %s: %w", fieldName, err)
+}
+
 // Represents a document retrieved from Solr.
 //
 // Data is a map with the field and values for each field
@@ -14,9 +31,16 @@ import (
 // Highlights is only populated when the document was returned
 // from a Search (i.e. not via Get). When populated contains the
 // field and values that matched the search.
+//
+// Children are the anonymous child documents of a parent/child block
+// (sent to Solr as _childDocuments_) and Nested the child documents
+// in labelled fields (e.g. "comments": [{...}, {...}]). See AddChild()
+// and AddNested().
 type Document struct {
 	Data       map[string]interface{}
 	Highlights map[string][]string
+	Children   []Document
+	Nested     map[string][]Document
 }
 
 // Created a new Document object.
@@ -26,9 +50,29 @@ func NewDocument() Document {
 	return Document{Data: data, Highlights: hl}
 }
 
+// Creates a Document from the data returned by Solr. Child documents
+// (in _childDocuments_ or in labelled fields as returned by the [child]
+// doc transformer) are moved from Data to Children and Nested. Other
+// object fields (i.e. without an id) are left in Data.
 func newDocumentFromSolrDoc(data documentRaw) Document {
 	hl := map[string][]string{}
-	return Document{Data: data, Highlights: hl}
+	doc := Document{Data: data, Highlights: hl}
+	for field, value := range data {
+		children, ok := childDocuments(field, value)
+		if !ok {
+			continue
+		}
+		if field == childDocumentsField {
+			doc.Children = children
+		} else {
+			if doc.Nested == nil {
+				doc.Nested = map[string][]Document{}
+			}
+			doc.Nested[field] = children
+		}
+		delete(data, field)
+	}
+	return doc
 }
 
 func newDocumentFromSolrResponse(raw responseRaw) []Document {
@@ -58,30 +102,154 @@ func (d Document) Value(fieldName string) string {
 // (mimics an array of one if the field is single value)
 func (d Document) Values(fieldName string) []string {
 	var values []string
-	dynValue := reflect.ValueOf(d.Data[fieldName])
-	kind := dynValue.Kind()
-	if kind == reflect.Invalid {
-		return values
+	for _, value := range toSlice(d.Data[fieldName]) {
+		values = append(values, convertString(value))
+	}
+	return values
+}
+
+// Returns the float value in a field (0 if the field has no
+// value or it cannot be converted to a float.)
+func (d Document) ValueFloat(fieldName string) float64 {
+	value, _ := d.ValueFloatErr(fieldName)
+	return value
+}
+
+// Returns the float value in a field or an error if the field has no
+// value or it cannot be converted to a float.
+func (d Document) ValueFloatErr(fieldName string) (float64, error) {
+	value, err := d.firstValue(fieldName)
+	if err != nil {
+		return 0, err
 	}
-	if kind == reflect.Slice {
-		for i := 0; i < dynValue.Len(); i++ {
-			strValue := fmt.Sprintf("%s", dynValue.Index(i))
-			values = append(values, strValue)
+	f, err := convertFloat64(value)
+	return f, fieldError(fieldName, err)
+}
+
+// Returns all the float values in a multi-value field (nil if any of
+// the values cannot be converted to a float.)
+func (d Document) ValuesFloat(fieldName string) []float64 {
+	values, _ := d.ValuesFloatErr(fieldName)
+	return values
+}
+
+// Returns all the float values in a multi-value field or an error if
+// any of the values cannot be converted to a float.
+func (d Document) ValuesFloatErr(fieldName string) ([]float64, error) {
+	var values []float64
+	for _, value := range toSlice(d.Data[fieldName]) {
+		f, err := convertFloat64(value)
+		if err != nil {
+			return nil, fieldError(fieldName, err)
 		}
-		return values
+		values = append(values, f)
+	}
+	return values, nil
+}
+
+// Returns the int value in a field (0 if the field has no value
+// or it cannot be converted to an int.)
+func (d Document) ValueInt(fieldName string) int {
+	value, _ := d.ValueIntErr(fieldName)
+	return value
+}
+
+// Returns the int value in a field or an error if the field has no
+// value or it cannot be converted to an int.
+func (d Document) ValueIntErr(fieldName string) (int, error) {
+	value, err := d.ValueInt64Err(fieldName)
+	if err != nil {
+		return 0, err
+	}
+	if int64(int(value)) != value {
+		return 0, fmt.Errorf("field %s: value %d overflows int", fieldName, value)
 	}
-	strValue := fmt.Sprintf("%s", dynValue)
-	values = append(values, strValue)
+	return int(value), nil
+}
+
+// Returns the int64 value in a field (0 if the field has no value
+// or it cannot be converted to an int64.)
+func (d Document) ValueInt64(fieldName string) int64 {
+	value, _ := d.ValueInt64Err(fieldName)
+	return value
+}
+
+// Returns the int64 value in a field or an error if the field has no
+// value or it cannot be converted to an int64.
+func (d Document) ValueInt64Err(fieldName string) (int64, error) {
+	value, err := d.firstValue(fieldName)
+	if err != nil {
+		return 0, err
+	}
+	i, err := convertInt64(value)
+	return i, fieldError(fieldName, err)
+}
+
+// Returns the bool value in a field (false if the field has no value
+// or it cannot be converted to a bool.)
+func (d Document) ValueBool(fieldName string) bool {
+	value, _ := d.ValueBoolErr(fieldName)
+	return value
+}
+
+// Returns the bool value in a field or an error if the field has no
+// value or it cannot be converted to a bool.
+func (d Document) ValueBoolErr(fieldName string) (bool, error) {
+	value, err := d.firstValue(fieldName)
+	if err != nil {
+		return false, err
+	}
+	b, err := convertBool(value)
+	return b, fieldError(fieldName, err)
+}
+
+// Returns the date value in a field (the zero time if the field has
+// no value or it cannot be parsed.) Solr dates are in ISO-8601 format
+// in UTC, e.g. 2019-01-02T03:04:05Z.
+func (d Document) ValueTime(fieldName string) time.Time {
+	value, _ := d.ValueTimeErr(fieldName)
+	return value
+}
+
+// Returns the date value in a field or an error if the field has no
+// value or it cannot be parsed.
+func (d Document) ValueTimeErr(fieldName string) (time.Time, error) {
+	value, err := d.firstValue(fieldName)
+	if err != nil {
+		return time.Time{}, err
+	}
+	t, err := convertTime(value)
+	return t, fieldError(fieldName, err)
+}
+
+// Returns all the date values in a multi-value field (nil if any of
+// the values cannot be parsed.)
+func (d Document) ValuesTime(fieldName string) []time.Time {
+	values, _ := d.ValuesTimeErr(fieldName)
 	return values
 }
 
-// Returns the float value in a field.
-func (d Document) ValueFloat(fieldName string) float64 {
-	value, ok := d.Data[fieldName].(float64)
-	if ok {
-		return value
+// Returns all the date values in a multi-value field or an error if
+// any of the values cannot be parsed.
+func (d Document) ValuesTimeErr(fieldName string) ([]time.Time, error) {
+	var values []time.Time
+	for _, value := range toSlice(d.Data[fieldName]) {
+		t, err := convertTime(value)
+		if err != nil {
+			return nil, fieldError(fieldName, err)
+		}
+		values = append(values, t)
 	}
-	return 0.0
+	return values, nil
+}
+
+// Returns the first value in a field (or ErrNoValue)
+func (d Document) firstValue(fieldName string) (interface{}, error) {
+	values := toSlice(d.Data[fieldName])
+	if len(values) == 0 {
+		return nil, fieldError(fieldName, ErrNoValue)
+	}
+	return values[0], nil
 }
 
 // Returns the value of the Id field.
@@ -89,6 +257,27 @@ func (d Document) Id() string {
 	return d.Value("id")
 }
 
+// Returns the value of the _version_ field (0 if the document does
+// not have one). Use it with UpdateIfVersion() for optimistic
+// concurrency.
+func (d Document) Version() int64 {
+	switch value := d.Data[versionField].(type) {
+	case int64:
+		return value
+	case int:
+		return int64(value)
+	case float64:
+		return int64(value)
+	case json.Number:
+		version, _ := value.Int64()
+		return version
+	case string:
+		version, _ := strconv.ParseInt(value, 10, 64)
+		return version
+	}
+	return 0
+}
+
 // Returns the highlights information for a given field name.
 func (d Document) HighlightsFor(field string) []string {
 	return d.Highlights[field]
@@ -1,7 +1,10 @@
 package solr
 
 import (
+	"encoding/json"
+	"errors"
 	"testing"
+	"time"
 )
 
 func TestDocument(t *testing.T) {
@@ -22,3 +25,63 @@ func TestDocument(t *testing.T) {
 		t.Errorf("TestDocument unexpected value: %v", s)
 	}
 }
+
+func TestDocumentTypedValues(t *testing.T) {
+	d := NewDocument()
+	d.Data["count"] = float64(42)
+	d.Data["big"] = json.Number("1640995200000000001")
+	d.Data["flag"] = true
+	d.Data["date"] = "2019-01-02T03:04:05Z"
+	d.Data["dates"] = []interface{}{"2019-01-02T03:04:05Z", "2020-01-02T03:04:05.5Z"}
+	d.Data["prices"] = []interface{}{float64(1.5), json.Number("2")}
+
+	if s := d.Value("count"); s != "42" {
+		t.Errorf("Unexpected string value: %s", s)
+	}
+
+	if i := d.ValueInt("count"); i != 42 {
+		t.Errorf("Unexpected int value: %d", i)
+	}
+
+	if i := d.ValueInt64("big"); i != 1640995200000000001 {
+		t.Errorf("Unexpected int64 value: %d", i)
+	}
+
+	if !d.ValueBool("flag") {
+		t.Errorf("Unexpected bool value")
+	}
+
+	date := time.Date(2019, 1, 2, 3, 4, 5, 0, time.UTC)
+	if v := d.ValueTime("date"); !v.Equal(date) {
+		t.Errorf("Unexpected time value: %v", v)
+	}
+
+	if v := d.ValuesTime("dates"); len(v) != 2 || !v[0].Equal(date) {
+		t.Errorf("Unexpected time values: %v", v)
+	}
+
+	if v := d.ValuesFloat("prices"); len(v) != 2 || v[0] != 1.5 || v[1] != 2 {
+		t.Errorf("Unexpected float values: %v", v)
+	}
+
+	if _, err := d.ValueIntErr("missing"); !errors.Is(err, ErrNoValue) {
+		t.Errorf("Expected ErrNoValue, got: %v", err)
+	}
+
+	if _, err := d.ValueIntErr("date"); err == nil {
+		t.Errorf("Expected a conversion error")
+	}
+}
+
+func TestDocumentVersionPrecision(t *testing.T) {
+	raw, err := NewResponseRaw([]byte(`{"response":{"numFound":1,"start":0,
+		"docs":[{"id":"1","_version_":1640995200123456789}]}}`))
+	if err != nil {
+		t.Fatalf("NewResponseRaw error: %s", err)
+	}
+
+	doc := newDocumentFromSolrDoc(raw.Data.Documents[0])
+	if v := doc.Version(); v != 1640995200123456789 {
+		t.Errorf("Unexpected version: %d", v)
+	}
+}
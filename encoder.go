@@ -0,0 +1,55 @@
+package solr
+
+import (
+	"encoding/json"
+	"io"
+)
+
+// DocumentEncoder writes documents as a JSON array to a stream.
+// See Solr.PostEncoded().
+type DocumentEncoder struct {
+	w     io.Writer
+	enc   *json.Encoder
+	count int
+}
+
+func newDocumentEncoder(w io.Writer) *DocumentEncoder {
+	return &DocumentEncoder{w: w, enc: json.NewEncoder(w)}
+}
+
+// Encode writes a document to the stream.
+func (e *DocumentEncoder) Encode(doc Document) error {
+	return e.EncodeOne(doc.solrData())
+}
+
+// EncodeOne writes a document to the stream. The map key represents
+// the field name and the map value the field value.
+func (e *DocumentEncoder) EncodeOne(datum map[string]interface{}) error {
+	separator := ","
+	if e.count == 0 {
+		separator = "["
+	}
+	if _, err := io.WriteString(e.w, separator); err != nil {
+		return err
+	}
+	if err := e.enc.Encode(datum); err != nil {
+		return err
+	}
+	e.count++
+	return nil
+}
+
+// Count returns the number of documents written so far.
+func (e *DocumentEncoder) Count() int {
+	return e.count
+}
+
+// close terminates the JSON array.
+func (e *DocumentEncoder) close() error {
+	end := "]"
+	if e.count == 0 {
+		end = "[]"
+	}
+	_, err := io.WriteString(e.w, end)
+	return err
+}
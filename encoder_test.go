@@ -0,0 +1,51 @@
+package solr
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestPostEncoded(t *testing.T) {
+	count := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		var data []map[string]interface{}
+		if err := json.Unmarshal(body, &data); err != nil {
+			// incomplete body (e.g. the writer failed)
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		count = len(data)
+		w.Write([]byte(`{"responseHeader":{"status":0}}`))
+	}))
+	defer server.Close()
+
+	solr := New(server.URL, false)
+	err := solr.PostEncoded(UpdateOptions{}, func(enc *DocumentEncoder) error {
+		for i := 0; i < 1000; i++ {
+			doc := map[string]interface{}{"id": fmt.Sprintf("%d", i)}
+			if err := enc.EncodeOne(doc); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil || count != 1000 {
+		t.Errorf("Unexpected result: %d %v", count, err)
+	}
+
+	writeErr := errors.New("no more data")
+	err = solr.PostEncodedContext(context.Background(), UpdateOptions{}, func(enc *DocumentEncoder) error {
+		enc.EncodeOne(map[string]interface{}{"id": "1"})
+		return writeErr
+	})
+	if err != writeErr {
+		t.Errorf("Expected the writer error, got: %v", err)
+	}
+}
@@ -0,0 +1,145 @@
+package solr
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// ErrVersionConflict can be used with errors.Is() to detect that an
+// update was rejected by Solr because the _version_ indicated did not
+// match the one in the index (HTTP 409).
+var ErrVersionConflict = errors.New("Solr version conflict")
+
+// ErrMultipleDocuments is returned by Get() when more than one
+// document matches the query. Use errors.Is() to detect it.
+var ErrMultipleDocuments = errors.New("More than one document was found")
+
+// ErrEmptyQuery is returned by DeleteByQuery() when the query is
+// empty (use DeleteAll() to delete all the documents.)
+var ErrEmptyQuery = errors.New("DeleteByQuery requires a query")
+
+// SolrError represents an error reported by Solr, either via the
+// HTTP status code or via the "error" section of its response.
+//
+// Use errors.As() to inspect it, or the IsXxx() helpers to branch on
+// the most common kinds of errors.
+type SolrError struct {
+	StatusCode     int    // HTTP status code of the response
+	Code           int    // Error code reported by Solr (usually the same as StatusCode)
+	Msg            string // Error message reported by Solr
+	ErrorClass     string // Java class of the error (from Solr's metadata)
+	RootErrorClass string // Java class of the root error (from Solr's metadata)
+	Trace          string // Stack trace reported by Solr (if any)
+	Url            string // URL of the request that failed
+	Body           string // Body of the response as returned by Solr
+}
+
+func (e *SolrError) Error() string {
+	msg := fmt.Sprintf("Solr Error. HTTP Status: %d", e.StatusCode)
+	if e.Code != 0 && e.Code != e.StatusCode {
+		msg += fmt.Sprintf(", Code: %d", e.Code)
+	}
+	if e.ErrorClass != "" {
+		msg += fmt.Sprintf(", Class: %s", e.ErrorClass)
+	}
+	if e.Msg != "" {
+		msg += fmt.Sprintf(". %s", e.Msg)
+	} else if e.Trace != "" {
+		msg += fmt.Sprintf(". %s", firstLine(e.Trace))
+	} else if e.Body != "" {
+		msg += fmt.Sprintf(". Body: %s", e.Body)
+	}
+	return msg
+}
+
+// Is allows errors.Is(err, ErrVersionConflict) to match a SolrError
+// reporting a version conflict.
+func (e *SolrError) Is(target error) bool {
+	return target == ErrVersionConflict && e.status() == http.StatusConflict
+}
+
+// newSolrError creates a SolrError from the HTTP status and the body
+// of a response. The body is parsed (if possible) to pick up the
+// error details reported by Solr.
+func newSolrError(url string, statusCode int, body []byte) *SolrError {
+	e := &SolrError{StatusCode: statusCode, Url: url, Body: string(body)}
+	response, err := NewResponseRaw(body)
+	if err == nil {
+		e.setDetails(response.Error)
+		if e.Code == 0 {
+			e.Code = response.Header.Status
+		}
+		if e.StatusCode == http.StatusOK && e.Code != 0 {
+			// Solr reported the error on the body
+			// but not via the HTTP status code.
+			e.StatusCode = e.Code
+		}
+	}
+	return e
+}
+
+func (e *SolrError) setDetails(raw errorRaw) {
+	e.Code = raw.Code
+	e.Msg = raw.Msg
+	e.Trace = raw.Trace
+	e.ErrorClass = raw.metadata("error-class")
+	e.RootErrorClass = raw.metadata("root-error-class")
+}
+
+func (e *SolrError) status() int {
+	if e.Code != 0 {
+		return e.Code
+	}
+	return e.StatusCode
+}
+
+// asSolrError returns the *SolrError in the chain of err (if any).
+func asSolrError(err error) (*SolrError, bool) {
+	var solrErr *SolrError
+	if errors.As(err, &solrErr) {
+		return solrErr, true
+	}
+	return nil, false
+}
+
+// IsNotFound returns true if err is a Solr error reporting that the
+// core or handler was not found (HTTP 404).
+func IsNotFound(err error) bool {
+	e, ok := asSolrError(err)
+	return ok && e.StatusCode == http.StatusNotFound
+}
+
+// IsSyntaxError returns true if err is a Solr error reporting that
+// the query could not be parsed.
+func IsSyntaxError(err error) bool {
+	e, ok := asSolrError(err)
+	if !ok || e.status() != http.StatusBadRequest {
+		return false
+	}
+	return strings.HasSuffix(e.ErrorClass, ".SyntaxError") ||
+		strings.HasSuffix(e.RootErrorClass, ".SyntaxError") ||
+		strings.Contains(e.Msg, "SyntaxError")
+}
+
+// IsVersionConflict returns true if err is a Solr error reporting a
+// _version_ conflict on an update (HTTP 409).
+func IsVersionConflict(err error) bool {
+	e, ok := asSolrError(err)
+	return ok && e.status() == http.StatusConflict
+}
+
+// IsUnavailable returns true if err is a Solr error reporting that
+// the service is temporarily unavailable (HTTP 503).
+func IsUnavailable(err error) bool {
+	e, ok := asSolrError(err)
+	return ok && e.status() == http.StatusServiceUnavailable
+}
+
+func firstLine(s string) string {
+	if i := strings.Index(s, "\n"); i >= 0 {
+		return strings.TrimSpace(s[:i])
+	}
+	return s
+}
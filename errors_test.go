@@ -0,0 +1,139 @@
+package solr
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestSolrError(t *testing.T) {
+	body := `{
+		"responseHeader":{"status":400,"QTime":1},
+		"error":{
+			"metadata":["error-class","org.apache.solr.common.SolrException",
+				"root-error-class","org.apache.solr.search.SyntaxError"],
+			"msg":"org.apache.solr.search.SyntaxError: Cannot parse 'title:('",
+			"code":400}}`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(body))
+	}))
+	defer server.Close()
+
+	solr := New(server.URL, false)
+	_, err := solr.SearchText("title:(")
+
+	var solrErr *SolrError
+	if !errors.As(fmt.Errorf("wrapped: %w", err), &solrErr) {
+		t.Fatalf("Expected a *SolrError, got: %v", err)
+	}
+	if solrErr.StatusCode != 400 || solrErr.Code != 400 {
+		t.Errorf("Unexpected status/code: %d/%d", solrErr.StatusCode, solrErr.Code)
+	}
+	if solrErr.ErrorClass != "org.apache.solr.common.SolrException" {
+		t.Errorf("Unexpected error class: %s", solrErr.ErrorClass)
+	}
+	if !IsSyntaxError(err) || IsNotFound(err) || IsVersionConflict(err) || IsUnavailable(err) {
+		t.Errorf("Unexpected error classification: %v", err)
+	}
+}
+
+func TestSolrErrorNotFound(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte("<html><body>Not Found</body></html>"))
+	}))
+	defer server.Close()
+
+	solr := New(server.URL, false)
+	_, err := solr.SearchText("hello")
+	if !IsNotFound(err) {
+		t.Errorf("Expected a not found error, got: %v", err)
+	}
+}
+
+func TestSolrErrorNotJSON(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte("<html><body>Proxy login</body></html>"))
+	}))
+	defer server.Close()
+
+	solr := New(server.URL, false)
+	_, err := solr.SearchText("hello")
+	var solrErr *SolrError
+	if !errors.As(err, &solrErr) || solrErr.StatusCode != http.StatusOK ||
+		!strings.Contains(solrErr.Error(), "text/html") || !strings.Contains(solrErr.Body, "Proxy login") {
+		t.Errorf("Unexpected error for a non-JSON response: %v", err)
+	}
+}
+
+func TestGetMultipleDocuments(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"responseHeader":{"status":0},"response":{"numFound":2,"docs":[{"id":"1"},{"id":"2"}]}}`))
+	}))
+	defer server.Close()
+
+	solr := New(server.URL, false)
+	_, err := solr.Get(NewGetParams("title:x", nil, map[string]string{}))
+	if !errors.Is(err, ErrMultipleDocuments) || !strings.Contains(err.Error(), "Q=title:x") {
+		t.Errorf("Expected ErrMultipleDocuments, got: %v", err)
+	}
+}
+
+func TestPostError(t *testing.T) {
+	body := `{"responseHeader":{"status":400,"QTime":1},
+		"error":{"msg":"ERROR: [doc=1] unknown field 'foo'","code":400}}`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/core/update" {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte("<html><body>Not Found</body></html>"))
+			return
+		}
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(body))
+	}))
+	defer server.Close()
+
+	data := map[string]interface{}{"id": "1", "foo": "bar"}
+	solr := New(server.URL+"/core", false)
+	err := solr.PostOne(data)
+	solrErr, ok := asSolrError(err)
+	if !ok || solrErr.Msg != "ERROR: [doc=1] unknown field 'foo'" {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	solr = New(server.URL+"/wrong-core", false)
+	if err := solr.PostOne(data); !IsNotFound(err) {
+		t.Errorf("Expected a not found error, got: %v", err)
+	}
+	if err := solr.Delete([]string{"1"}); !IsNotFound(err) {
+		t.Errorf("Expected a not found error, got: %v", err)
+	}
+}
+
+func TestVersionConflict(t *testing.T) {
+	body := `{"responseHeader":{"status":409,"QTime":1},
+		"error":{"msg":"version conflict for 123 expected=1 actual=2","code":409}}`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusConflict)
+		w.Write([]byte(body))
+	}))
+	defer server.Close()
+
+	doc := NewDocument()
+	doc.Data["id"] = "123"
+	doc.Data["_version_"] = float64(2)
+	if doc.Version() != 2 {
+		t.Errorf("Unexpected version: %d", doc.Version())
+	}
+
+	solr := New(server.URL, false)
+	err := solr.UpdateIfVersion(doc, 1)
+	if !errors.Is(err, ErrVersionConflict) || !IsVersionConflict(err) {
+		t.Errorf("Expected a version conflict error, got: %v", err)
+	}
+}
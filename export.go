@@ -0,0 +1,158 @@
+package solr
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+)
+
+// Export streams all the documents that match the query via Solr's
+// /export handler calling fn for each document. The response is
+// decoded incrementally so memory usage stays constant regardless of
+// the number of documents.
+//
+// As required by the /export handler fl and sort must only reference
+// fields with docValues. If fn returns an error the export is stopped
+// and the error is returned.
+func (s Solr) Export(q string, fl []string, sort []SortClause, fn func(Document) error) error {
+	return s.ExportContext(context.Background(), q, fl, sort, fn)
+}
+
+// ExportContext is like Export but uses the provided context for the
+// HTTP request to Solr.
+func (s Solr) ExportContext(ctx context.Context, q string, fl []string, sort []SortClause, fn func(Document) error) error {
+	if len(fl) == 0 || len(sort) == 0 {
+		return errors.New("Export requires fl and sort")
+	}
+
+	qs := qsAddDefault("q", q, "*:*")
+	qs += qsAddMany("fl", fl)
+	qs += qsAdd("sort", sortToString(sort))
+	url := s.CoreUrl + "/export?" + qs
+
+	s.log("Solr HTTP GET", url)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return err
+	}
+
+	r, err := s.do(req)
+	if err != nil {
+		return contextError(ctx, err)
+	}
+	defer r.Body.Close()
+
+	if r.StatusCode < 200 || r.StatusCode > 299 {
+		body, _ := ioutil.ReadAll(r.Body)
+		return newSolrError(url, r.StatusCode, body)
+	}
+
+	// Keep track of the errors returned by fn so that they are returned
+	// as-is rather than being replaced by the context's error.
+	fnFailed := false
+	err = exportDecode(r.Body, url, func(doc Document) error {
+		fnErr := fn(doc)
+		fnFailed = fnErr != nil
+		return fnErr
+	})
+	if err == nil || fnFailed {
+		return err
+	}
+	return contextError(ctx, err)
+}
+
+// exportDecode decodes the documents in the response of the /export
+// handler one at a time, i.e. {"response":{"numFound":N,"docs":[...]}}
+func exportDecode(body io.Reader, url string, fn func(Document) error) error {
+	decoder := json.NewDecoder(body)
+	decoder.UseNumber()
+
+	if err := expectDelim(decoder, '{'); err != nil {
+		return err
+	}
+	for decoder.More() {
+		key, err := decoder.Token()
+		if err != nil {
+			return err
+		}
+
+		switch key {
+		case "response":
+			if err := exportDecodeResponse(decoder, url, fn); err != nil {
+				return err
+			}
+		case "error":
+			var raw errorRaw
+			if err := decoder.Decode(&raw); err != nil {
+				return err
+			}
+			e := &SolrError{StatusCode: http.StatusOK, Url: url}
+			e.setDetails(raw)
+			return e
+		default:
+			var skip json.RawMessage
+			if err := decoder.Decode(&skip); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func exportDecodeResponse(decoder *json.Decoder, url string, fn func(Document) error) error {
+	if err := expectDelim(decoder, '{'); err != nil {
+		return err
+	}
+	for decoder.More() {
+		key, err := decoder.Token()
+		if err != nil {
+			return err
+		}
+
+		if key != "docs" {
+			var skip json.RawMessage
+			if err := decoder.Decode(&skip); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if err := expectDelim(decoder, '['); err != nil {
+			return err
+		}
+		for decoder.More() {
+			var data documentRaw
+			if err := decoder.Decode(&data); err != nil {
+				return err
+			}
+			if exception, ok := data["EXCEPTION"]; ok {
+				// The /export handler reports errors that happen
+				// while streaming as a document.
+				msg := fmt.Sprintf("%v", exception)
+				return &SolrError{StatusCode: http.StatusOK, Msg: msg, Url: url}
+			}
+			if err := fn(newDocumentFromSolrDoc(data)); err != nil {
+				return err
+			}
+		}
+		if err := expectDelim(decoder, ']'); err != nil {
+			return err
+		}
+	}
+	return expectDelim(decoder, '}')
+}
+
+func expectDelim(decoder *json.Decoder, delim json.Delim) error {
+	token, err := decoder.Token()
+	if err != nil {
+		return err
+	}
+	if token != delim {
+		return fmt.Errorf("unexpected token in Solr response: %v (expected %v)", token, delim)
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+package solr
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestExport(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/export" || r.URL.Query().Get("sort") != "id asc" {
+			t.Errorf("Unexpected request: %s", r.URL)
+		}
+		fmt.Fprint(w, `{"responseHeader":{"status":0},"response":{"numFound":3,"docs":[`)
+		fmt.Fprint(w, `{"id":"1","year_i":2001},{"id":"2"},{"id":"3"}]}}`)
+	}))
+	defer server.Close()
+
+	solr := New(server.URL, false)
+	ids := ""
+	err := solr.Export("", []string{"id"}, []SortClause{Asc("id")}, func(doc Document) error {
+		ids += doc.Id()
+		return nil
+	})
+	if err != nil || ids != "123" {
+		t.Errorf("Unexpected export: %s %v", ids, err)
+	}
+
+	stop := errors.New("stop")
+	err = solr.Export("", []string{"id"}, []SortClause{Asc("id")}, func(doc Document) error {
+		return stop
+	})
+	if err != stop {
+		t.Errorf("Expected the callback error, got: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	err = solr.ExportContext(ctx, "", []string{"id"}, []SortClause{Asc("id")}, func(doc Document) error {
+		cancel()
+		return stop
+	})
+	if err != stop {
+		t.Errorf("Expected the callback error after cancel, got: %v", err)
+	}
+
+	ctx, cancel = context.WithCancel(context.Background())
+	err = solr.ExportContext(ctx, "", []string{"id"}, []SortClause{Asc("id")}, func(doc Document) error {
+		if doc.Id() == "3" {
+			cancel()
+		}
+		return nil
+	})
+	if err != nil {
+		t.Errorf("Expected no error for a completed export, got: %v", err)
+	}
+}
+
+type exportTestError struct {
+	ids []string
+}
+
+func (e exportTestError) Error() string {
+	return fmt.Sprintf("failed on %v", e.ids)
+}
+
+func TestExportUncomparableError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"responseHeader":{"status":0},"response":{"numFound":1,"docs":[{"id":"1"}]}}`)
+	}))
+	defer server.Close()
+
+	solr := New(server.URL, false)
+	err := solr.Export("", []string{"id"}, []SortClause{Asc("id")}, func(doc Document) error {
+		return exportTestError{ids: []string{doc.Id()}}
+	})
+	if _, ok := err.(exportTestError); !ok {
+		t.Errorf("Expected the callback error, got: %v", err)
+	}
+}
+
+func TestExportException(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"responseHeader":{"status":0},"response":{"numFound":1,"docs":[`)
+		fmt.Fprint(w, `{"EXCEPTION":"title_t must have DocValues to use this feature."}]}}`)
+	}))
+	defer server.Close()
+
+	solr := New(server.URL, false)
+	err := solr.Export("", []string{"title_t"}, []SortClause{Asc("id")}, func(doc Document) error {
+		return nil
+	})
+	if _, ok := asSolrError(err); !ok {
+		t.Errorf("Expected a SolrError, got: %v", err)
+	}
+}
@@ -0,0 +1,67 @@
+package solr
+
+import (
+	"github.com/hectorcorrea/solr/query"
+)
+
+// FacetQuery represents a named query to use as a facet value
+// (Solr's facet.query), e.g. "Available online" for "online:true"
+// or "Last 30 days" for "date:[NOW-30DAYS TO NOW]".
+type FacetQuery struct {
+	Label string // Text to display (also used as the value in the URLs)
+	Query string // Query to send to Solr
+}
+
+// NewQueryFacet creates the definition of a facet whose values are
+// the counts of the queries indicated. The field is the name used to
+// identify the facet in the URLs (it does not need to be a field in
+// Solr). Filtering by a value uses "fq=field|label" in the query
+// string, for example:
+//
+// 	facet := solr.NewQueryFacet("when", "Date added",
+// 		solr.FacetQuery{Label: "Last 30 days", Query: "date:[NOW-30DAYS TO NOW]"},
+// 		solr.FacetQuery{Label: "Last year", Query: "date:[NOW-1YEAR TO NOW]"})
+// 	params.Facets = append(params.Facets, facet)
+func NewQueryFacet(field, title string, queries ...FacetQuery) FacetField {
+	return FacetField{Field: field, Title: title, Queries: queries}
+}
+
+// queryForLabel returns the query with the label indicated.
+func (ff FacetField) queryForLabel(label string) (FacetQuery, bool) {
+	for _, q := range ff.Queries {
+		if q.Label == label {
+			return q, true
+		}
+	}
+	return FacetQuery{}, false
+}
+
+// facetQueryKey is the key used for the facet query in the Solr
+// request and response. Including the field makes sure the same label
+// can be used in different facets.
+func facetQueryKey(field, label string) string {
+	return field + "|" + label
+}
+
+func (ff FacetField) facetQueriesToQueryString() string {
+	qs := ""
+	for _, q := range ff.Queries {
+		key := facetQueryKey(ff.Field, q.Label)
+		lp := query.LocalParams("", query.Raw(q.Query)).With("key", key)
+		if ff.MultiSelect {
+			lp = lp.With("ex", ff.tag())
+		}
+		qs += qsAdd("facet.query", lp.String())
+	}
+	return qs
+}
+
+// Adds the values for a query facet from the facet_queries in the
+// Solr response (in the order of the definition.)
+func (ff *FacetField) addQueryValues(counts map[string]interface{}, fqs filterQueries) {
+	for _, q := range ff.Queries {
+		count, _ := convertInt64(counts[facetQueryKey(ff.Field, q.Label)])
+		active := fqs.HasFieldValue(ff.Field, q.Label)
+		ff.addValue(q.Label, int(count), active)
+	}
+}
@@ -0,0 +1,55 @@
+package solr
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestFacetQueries(t *testing.T) {
+	raw, err := NewResponseRaw([]byte(`{"response":{"numFound":3,"start":0,"docs":[]},
+		"facet_counts":{"facet_queries":{"when|Last 30 days":2,"when|Last year":5}}}`))
+	if err != nil {
+		t.Fatalf("NewResponseRaw error: %s", err)
+	}
+
+	qs := url.Values{"fq": []string{"when|Last year"}}
+	params := NewSearchParamsFromQs(qs, map[string]string{}, map[string]string{})
+	params.Facets = Facets{NewQueryFacet("when", "Date added",
+		FacetQuery{Label: "Last 30 days", Query: "date:[NOW-30DAYS TO NOW]"},
+		FacetQuery{Label: "Last year", Query: "date:[NOW-1YEAR TO NOW]"})}
+
+	s := params.toSolrQueryString()
+	expected := []string{
+		"fq=date%3A%5BNOW-1YEAR+TO+NOW%5D&",
+		"facet.query=%7B%21key%3D%22when%7CLast+30+days%22%7Ddate%3A%5BNOW-30DAYS+TO+NOW%5D&",
+	}
+	for _, e := range expected {
+		if !strings.Contains(s, e) {
+			t.Errorf("Missing %s in SearchParams URL: %s", e, s)
+		}
+	}
+
+	r := newSearchResponse(params, raw)
+	values := r.Facets[0].Values
+	if len(values) != 2 || values[0].Text != "Last 30 days" || values[0].Count != 2 ||
+		values[0].Active || !values[1].Active {
+		t.Errorf("Unexpected facet query values: %#v", values)
+	}
+
+	if r.Url != "q=%2A&fq=when|Last+year&" {
+		t.Errorf("Unexpected Url: %s", r.Url)
+	}
+
+	r.Facets.SetAddRemoveUrls(r.Url)
+	if values[0].AddUrl != "q=%2A&fq=when|Last+year&&fq=when|Last+30+days&" || values[1].RemoveUrl != "q=%2A&" {
+		t.Errorf("Unexpected URLs: %s / %s", values[0].AddUrl, values[1].RemoveUrl)
+	}
+
+	// round trip the AddUrl through NewSearchParamsFromQs
+	qs, _ = url.ParseQuery(values[0].AddUrl)
+	params2 := NewSearchParamsFromQs(qs, map[string]string{}, map[string]string{})
+	if !params2.FilterQueries.HasFieldValue("when", "Last 30 days") {
+		t.Errorf("Unexpected filter queries: %#v", params2.FilterQueries)
+	}
+}
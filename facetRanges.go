@@ -0,0 +1,203 @@
+package solr
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/hectorcorrea/solr/query"
+)
+
+// FacetRange represents the definition of a range facet (numeric or
+// date) as defined by Solr's facet.range parameters.
+type FacetRange struct {
+	Start   string   // e.g. "0" or "NOW/YEAR-10YEARS"
+	End     string   // e.g. "100" or "NOW/YEAR"
+	Gap     string   // e.g. "10" or "+1YEAR"
+	Other   []string // before, after, between, none, or all
+	Include []string // lower, upper, edge, outer, or all (Solr's default is lower)
+}
+
+// NewRangeFacet creates the definition of a range facet.
+func NewRangeFacet(field, title, start, end, gap string) FacetField {
+	r := FacetRange{Start: start, End: end, Gap: gap}
+	return FacetField{Field: field, Title: title, Range: &r}
+}
+
+// NewIntervalFacet creates the definition of an interval facet. The
+// intervals are in Solr's syntax, e.g. "[0,10)" or "[10,*]", and can
+// include a label, e.g. "{!key=cheap}[0,10)".
+func NewIntervalFacet(field, title string, intervals ...string) FacetField {
+	return FacetField{Field: field, Title: title, Intervals: intervals}
+}
+
+func (r FacetRange) toQueryString(field, facetParam string) string {
+	prefix := "f." + field + ".facet.range."
+	qs := qsAdd("facet.range", facetParam)
+	qs += qsAdd(prefix+"start", r.Start)
+	qs += qsAdd(prefix+"end", r.End)
+	qs += qsAdd(prefix+"gap", r.Gap)
+	for _, other := range r.Other {
+		qs += qsAdd(prefix+"other", other)
+	}
+	for _, include := range r.Include {
+		qs += qsAdd(prefix+"include", include)
+	}
+	return qs
+}
+
+func (r FacetRange) includes(option string) bool {
+	for _, include := range r.Include {
+		if include == option || include == "all" {
+			return true
+		}
+	}
+	return false
+}
+
+// bucketRange returns the range to filter by the bucket that starts
+// at "from". first and last indicate if the bucket is the first or
+// last bucket (which matters for the "edge" include option).
+func (r FacetRange) bucketRange(from, gap string, first, last bool) query.RangeQuery {
+	return r.boundedRange(from, addGap(from, gap), first, last)
+}
+
+// betweenRange returns the range to filter by the "between" bucket,
+// i.e. all the values from start to end.
+func (r FacetRange) betweenRange(start, end string) query.RangeQuery {
+	return r.boundedRange(start, end, true, true)
+}
+
+// boundedRange returns the range from/to honoring the include options.
+func (r FacetRange) boundedRange(from, to string, first, last bool) query.RangeQuery {
+	q := query.RangeQuery{From: from, To: to}
+	lower := len(r.Include) == 0 || r.includes("lower") || (first && r.includes("edge"))
+	upper := r.includes("upper") || (last && r.includes("edge"))
+	q.ExcludeFrom = !lower
+	q.ExcludeTo = !upper
+	return q
+}
+
+// addGap returns the value + gap. Date gaps (e.g. "+1YEAR") are
+// appended as date math so Solr calculates them.
+func addGap(value, gap string) string {
+	v, err1 := strconv.ParseFloat(value, 64)
+	g, err2 := strconv.ParseFloat(gap, 64)
+	if err1 == nil && err2 == nil {
+		return strconv.FormatFloat(v+g, 'f', -1, 64)
+	}
+	if !strings.HasPrefix(gap, "+") && !strings.HasPrefix(gap, "-") {
+		gap = "+" + gap
+	}
+	return value + gap
+}
+
+var intervalRegEx = regexp.MustCompile(`^\s*(\{!.*\})?\s*([\[\(])\s*([^,]*?)\s*,\s*([^,]*?)\s*([\]\)])\s*$`)
+
+// parseInterval parses an interval in Solr's syntax, e.g.
+// "{!key=cheap}[0,10)", into its label (the key Solr uses in the
+// response) and the equivalent range.
+func parseInterval(interval string) (string, query.RangeQuery, bool) {
+	tokens := intervalRegEx.FindStringSubmatch(interval)
+	if tokens == nil {
+		return "", query.RangeQuery{}, false
+	}
+
+	label := strings.TrimSpace(interval)
+	if tokens[1] != "" {
+		label = strings.TrimPrefix(strings.TrimSuffix(tokens[1], "}"), "{!")
+		label = strings.TrimPrefix(strings.TrimSpace(label), "key=")
+		label = strings.Trim(label, `"'`)
+		label = strings.TrimSpace(label)
+	}
+
+	q := query.RangeQuery{
+		From:        tokens[3],
+		To:          tokens[4],
+		ExcludeFrom: tokens[2] == "(",
+		ExcludeTo:   tokens[5] == ")",
+	}
+	return label, q, true
+}
+
+var rangeRegEx = regexp.MustCompile(`^\s*([\[\{])\s*(\S+)\s+TO\s+(\S+)\s*([\]\}])\s*$`)
+
+// parseRange parses a filter value in the form "[x TO y}" into a
+// RangeQuery.
+func parseRange(value string) (query.RangeQuery, bool) {
+	tokens := rangeRegEx.FindStringSubmatch(value)
+	if tokens == nil {
+		return query.RangeQuery{}, false
+	}
+	q := query.RangeQuery{
+		From:        tokens[2],
+		To:          tokens[3],
+		ExcludeFrom: tokens[1] == "{",
+		ExcludeTo:   tokens[4] == "}",
+	}
+	return q, true
+}
+
+// rangeFilterValue returns the value to use in the fq for a range
+// (i.e. the range without the field, e.g. "[0 TO 10}").
+func rangeFilterValue(q query.RangeQuery) string {
+	q.Field = ""
+	return q.String()
+}
+
+// Adds the values for a range facet from the Solr response.
+func (ff *FacetField) addRangeValues(raw facetRangeRaw, fqs filterQueries) {
+	gap := convertString(raw.Gap)
+	start := convertString(raw.Start)
+	end := convertString(raw.End)
+	def := FacetRange{}
+	if ff.Range != nil {
+		def = *ff.Range
+	}
+
+	if raw.Before != nil {
+		q := query.RangeQuery{From: "*", To: start, ExcludeTo: !def.includes("outer")}
+		ff.addRangeValue("before", q, raw.Before, fqs)
+	}
+
+	for i := 0; i+1 < len(raw.Counts); i += 2 {
+		from := convertString(raw.Counts[i])
+		first, last := i == 0, i+2 >= len(raw.Counts)
+		q := def.bucketRange(from, gap, first, last)
+		ff.addRangeValue(from, q, raw.Counts[i+1], fqs)
+	}
+
+	if raw.After != nil {
+		q := query.RangeQuery{From: end, To: "*", ExcludeFrom: !def.includes("outer")}
+		ff.addRangeValue("after", q, raw.After, fqs)
+	}
+
+	if raw.Between != nil {
+		q := def.betweenRange(start, end)
+		ff.addRangeValue("between", q, raw.Between, fqs)
+	}
+}
+
+// Adds the values for an interval facet from the Solr response.
+// counts are keyed by the interval (or its label).
+func (ff *FacetField) addIntervalValues(counts map[string]interface{}, fqs filterQueries) {
+	for _, interval := range ff.Intervals {
+		label, q, ok := parseInterval(interval)
+		if !ok {
+			continue
+		}
+		ff.addRangeValue(label, q, counts[label], fqs)
+	}
+}
+
+func (ff *FacetField) addRangeValue(text string, q query.RangeQuery, rawCount interface{}, fqs filterQueries) {
+	count, _ := convertInt64(rawCount)
+	filter := rangeFilterValue(q)
+	value := FacetValue{
+		Text:   text,
+		Filter: filter,
+		Count:  int(count),
+		Active: fqs.HasFieldValue(ff.Field, filter),
+	}
+	ff.Values = append(ff.Values, value)
+}
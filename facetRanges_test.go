@@ -0,0 +1,106 @@
+package solr
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestRangeFacetsUrl(t *testing.T) {
+	params := NewSearchParams("*", map[string]string{}, map[string]string{})
+	year := NewRangeFacet("year_i", "Year", "1900", "2000", "50")
+	year.Range.Other = []string{"before"}
+	params.Facets = Facets{year, NewIntervalFacet("price_f", "Price", "{!key=cheap}[0,10)", "[10,*]")}
+	qs := params.toSolrQueryString()
+	expected := []string{
+		"facet.range=year_i&",
+		"f.year_i.facet.range.start=1900&",
+		"f.year_i.facet.range.gap=50&",
+		"f.year_i.facet.range.other=before&",
+		"facet.interval=price_f&",
+		"f.price_f.facet.interval.set=%7B%21key%3Dcheap%7D%5B0%2C10%29&",
+	}
+	for _, s := range expected {
+		if !strings.Contains(qs, s) {
+			t.Errorf("Missing %s in SearchParams URL: %s", s, qs)
+		}
+	}
+}
+
+func TestRangeFacets(t *testing.T) {
+	raw, err := NewResponseRaw([]byte(`{"response":{"numFound":3,"start":0,"docs":[]},
+		"facet_counts":{
+			"facet_ranges":{
+				"year_i":{"counts":["1900",2,"1950",1],"gap":50,"start":1900,"end":2000,"before":4},
+				"date_dt":{"counts":["2019-01-01T00:00:00Z",3],"gap":"+1YEAR",
+					"start":"2019-01-01T00:00:00Z","end":"2020-01-01T00:00:00Z"}},
+			"facet_intervals":{"price_f":{"cheap":5,"[10,*]":7}}}}`))
+	if err != nil {
+		t.Fatalf("NewResponseRaw error: %s", err)
+	}
+
+	qs := url.Values{"fq": []string{"year_i|[1950 TO 2000}", "price_f|[10 TO *]"}}
+	params := NewSearchParamsFromQs(qs, map[string]string{}, map[string]string{})
+	params.Facets = Facets{
+		NewRangeFacet("year_i", "Year", "1900", "2000", "50"),
+		NewRangeFacet("date_dt", "Date", "NOW/YEAR-1YEAR", "NOW/YEAR", "+1YEAR"),
+		NewIntervalFacet("price_f", "Price", "{!key=cheap}[0,10)", "[10,*]"),
+	}
+	r := newSearchResponse(params, raw)
+	if len(r.Facets) != 3 {
+		t.Fatalf("Unexpected facets: %#v", r.Facets)
+	}
+
+	years := r.Facets[0].Values
+	if len(years) != 3 || years[0].Text != "before" || years[0].Filter != "[* TO 1900}" || years[0].Count != 4 ||
+		years[1].Filter != "[1900 TO 1950}" || years[1].Active || !years[2].Active {
+		t.Errorf("Unexpected range values: %#v", years)
+	}
+
+	dates := r.Facets[1].Values
+	if len(dates) != 1 || dates[0].Filter != "[2019-01-01T00:00:00Z TO 2019-01-01T00:00:00Z+1YEAR}" {
+		t.Errorf("Unexpected date range values: %#v", dates)
+	}
+
+	prices := r.Facets[2].Values
+	if len(prices) != 2 || prices[0].Text != "cheap" || prices[0].Filter != "[0 TO 10}" ||
+		prices[0].Count != 5 || prices[0].Active || !prices[1].Active {
+		t.Errorf("Unexpected interval values: %#v", prices)
+	}
+
+	r.Facets.SetAddRemoveUrls(r.Url)
+	if r.Facets[0].Values[2].RemoveUrl != "q=%2A&fq=price_f|%5B10+TO+%2A%5D&" {
+		t.Errorf("Unexpected RemoveUrl: %s", r.Facets[0].Values[2].RemoveUrl)
+	}
+
+	fqs := params.FilterQueries.toQueryString(params.Facets)
+	if fqs != "fq=year_i:%5B1950+TO+2000%7D&fq=price_f:%5B10+TO+%2A%5D&" {
+		t.Errorf("Unexpected range filter queries: %s", fqs)
+	}
+}
+
+func TestRangeFacetsBetween(t *testing.T) {
+	raw, err := NewResponseRaw([]byte(`{"response":{"numFound":3,"start":0,"docs":[]},
+		"facet_counts":{"facet_ranges":{
+			"year_i":{"counts":["1900",2,"1950",1],"gap":50,"start":1900,"end":2000,"between":3}}}}`))
+	if err != nil {
+		t.Fatalf("NewResponseRaw error: %s", err)
+	}
+
+	params := NewSearchParams("*", map[string]string{}, map[string]string{})
+	year := NewRangeFacet("year_i", "Year", "1900", "2000", "50")
+	year.Range.Other = []string{"between"}
+	params.Facets = Facets{year}
+	r := newSearchResponse(params, raw)
+	values := r.Facets[0].Values
+	if len(values) != 3 || values[2].Text != "between" || values[2].Filter != "[1900 TO 2000}" || values[2].Count != 3 {
+		t.Errorf("Unexpected between value: %#v", values)
+	}
+
+	year.Range.Include = []string{"edge"}
+	r = newSearchResponse(params, raw)
+	values = r.Facets[0].Values
+	if values[0].Filter != "[1900 TO 1950}" || values[1].Filter != "{1950 TO 2000]" || values[2].Filter != "[1900 TO 2000]" {
+		t.Errorf("Unexpected edge values: %#v", values)
+	}
+}
@@ -5,6 +5,8 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+
+	"github.com/hectorcorrea/solr/query"
 )
 
 // Facets is an array of FacetField definitions
@@ -12,10 +14,19 @@ type Facets []FacetField
 
 // FacetField represents a single facet field definition.
 type FacetField struct {
-	Field  string       // Name of the field in Solr.
-	Title  string       // Display title for the field.
-	Order  int          // Order of this field on the Facets
-	Values []FacetValue // Values returned by Solr for this field.
+	Field     string       // Name of the field in Solr.
+	Title     string       // Display title for the field.
+	Order     int          // Order of this field on the Facets
+	Values    []FacetValue // Values returned by Solr for this field.
+	Range     *FacetRange  // Range definition (for range facets only)
+	Intervals []string     // Intervals (for interval facets only)
+	Queries   []FacetQuery // Queries (for query facets only)
+
+	// MultiSelect allows selecting several values of this facet. The
+	// selected values are combined in a single OR filter and the counts
+	// for the facet exclude that filter so that the other values are
+	// still displayed (via Solr's tag/ex local params).
+	MultiSelect bool
 }
 
 // AddUrl and RemoveUrl are leaky abstraction since they are only
@@ -23,6 +34,7 @@ type FacetField struct {
 // things a lot upstream.
 type FacetValue struct {
 	Text      string // Value returned by Solr for this field.
+	Filter    string // Value to filter by (if different from Text, e.g. "[0 TO 10}" for ranges)
 	Count     int    // Number of documents that matched this field/value.
 	Active    bool   // true if we are filtering by this facet value
 	AddUrl    string // URL to filter by this value. See SetAddRemoveUrls()
@@ -77,13 +89,21 @@ func (facets Facets) SetAddRemoveUrls(baseUrl string) {
 	for _, facet := range facets {
 		for i, value := range facet.Values {
 			// fqValRaw := "fq=" + facet.Field + "|" + value.Text + "&"
-			fqValEncoded := "fq=" + facet.Field + "|" + url.QueryEscape(value.Text) + "&"
+			fqValEncoded := "fq=" + facet.Field + "|" + url.QueryEscape(value.filter()) + "&"
 			facet.Values[i].RemoveUrl = strings.Replace(baseUrl, fqValEncoded, "", 1)
 			facet.Values[i].AddUrl = baseUrl + "&" + fqValEncoded
 		}
 	}
 }
 
+// filter returns the value to use on the fq to filter by this value.
+func (value FacetValue) filter() string {
+	if value.Filter != "" {
+		return value.Filter
+	}
+	return value.Text
+}
+
 func (ff *FacetField) addValue(text string, count int, active bool) {
 	value := FacetValue{
 		Text:   text,
@@ -93,12 +113,38 @@ func (ff *FacetField) addValue(text string, count int, active bool) {
 	ff.Values = append(ff.Values, value)
 }
 
+// tag returns the tag used for the filters of a multi-select facet.
+func (ff FacetField) tag() string {
+	return ff.Field
+}
+
+// facetParam returns the value of the facet.field (or facet.range,
+// or facet.interval) parameter for this facet, e.g. "{!ex=x}x" for
+// multi-select facets.
+func (ff FacetField) facetParam() string {
+	if ff.MultiSelect {
+		return query.LocalParams("", query.Raw(ff.Field)).With("ex", ff.tag()).String()
+	}
+	return ff.Field
+}
+
 func (facets Facets) toQueryString() string {
 	qs := ""
 	if len(facets) > 0 {
 		qs += qsAdd("facet", "on")
 		for _, f := range facets {
-			qs += qsAdd("facet.field", f.Field)
+			if f.Range != nil {
+				qs += f.Range.toQueryString(f.Field, f.facetParam())
+			} else if len(f.Queries) > 0 {
+				qs += f.facetQueriesToQueryString()
+			} else if len(f.Intervals) > 0 {
+				qs += qsAdd("facet.interval", f.facetParam())
+				for _, interval := range f.Intervals {
+					qs += qsAdd("f."+f.Field+".facet.interval.set", interval)
+				}
+			} else {
+				qs += qsAdd("facet.field", f.facetParam())
+			}
 			// The rest of the facet filters (mincount, limit, offset)
 			// must be defined by the client.
 		}
@@ -4,7 +4,10 @@ import (
 	"fmt"
 	// "log"
 	"net/url"
+	"regexp"
 	"strings"
+
+	"github.com/hectorcorrea/solr/query"
 )
 
 type filterQuery struct {
@@ -14,13 +17,19 @@ type filterQuery struct {
 
 type filterQueries []filterQuery
 
+// fieldNameRegEx matches plain Solr field names (e.g. "subject" or
+// "year_i"). Anything else in a filter (e.g. "title:x OR *") would
+// alter the filter query sent to Solr.
+var fieldNameRegEx = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_.\-]*$`)
+
 // NewFilterQueries creates a new object from an array of values.
-// values are the "fq=x|y" that came on the query string.
+// values are the "fq=x|y" that came on the query string. Values with
+// an invalid field name are ignored.
 func newFilterQueries(values []string) filterQueries {
 	fqs := filterQueries{}
 	for _, value := range values {
 		tokens := strings.Split(value, "|")
-		if len(tokens) == 2 {
+		if len(tokens) == 2 && fieldNameRegEx.MatchString(tokens[0]) {
 			// value := url.QueryEscape(tokens[1])
 			value := tokens[1]
 			fq := filterQuery{Field: tokens[0], Value: value}
@@ -32,7 +41,7 @@ func newFilterQueries(values []string) filterQueries {
 
 func (fqs filterQueries) HasFieldValue(field, value string) bool {
 	for _, fq := range fqs {
-		if fq.Field == field && fq.Value == value {
+		if fq.Field == field && fq.equalValue(value) {
 			return true
 		}
 	}
@@ -49,15 +58,73 @@ func (fqs filterQueries) FieldValues(field string) []string {
 	return values
 }
 
-func (fqs filterQueries) toQueryString() string {
+// toQueryString returns the fq parameters to send to Solr. The facets
+// definitions are used to resolve the filters for query facets (e.g.
+// "fq=when|Last year" is sent as "fq=date:[NOW-1YEAR TO NOW]") and to
+// combine the values of multi-select facets into a single tagged OR
+// filter (e.g. "fq={!tag=format}format:"Book" OR format:"Map"")
+func (fqs filterQueries) toQueryString(facets Facets) string {
 	str := ""
+	done := map[string]bool{}
 	for _, fq := range fqs {
+		facet, isFacet := facets.ForField(fq.Field)
+		if isFacet && facet.MultiSelect {
+			if done[fq.Field] {
+				continue
+			}
+			done[fq.Field] = true
+			clauses := []string{}
+			for _, value := range fqs.FieldValues(fq.Field) {
+				field := filterQuery{Field: fq.Field, Value: value}
+				clauses = append(clauses, field.query(facet))
+			}
+			q := query.LocalParams("", query.Raw(strings.Join(clauses, " OR "))).With("tag", facet.tag())
+			str += qsAdd("fq", q.String())
+			continue
+		}
+
+		if isFacet && len(facet.Queries) > 0 {
+			if q, ok := facet.queryForLabel(fq.Value); ok {
+				str += qsAdd("fq", q.Query)
+				continue
+			}
+		}
 		str += fmt.Sprintf("fq=%s&", fq.toQueryString())
 	}
 	return str
 }
 
+// query returns the (unencoded) query for the filter, e.g.
+// subject:"abc xyz", year:[2000 TO 2010}, or (date:[NOW-1YEAR TO NOW])
+// for query facets.
+func (fq filterQuery) query(facet FacetField) string {
+	if q, ok := facet.queryForLabel(fq.Value); ok {
+		return "(" + q.Query + ")"
+	}
+	if q, ok := parseRange(fq.Value); ok {
+		return fq.Field + ":" + rangeFilterValue(q)
+	}
+	return fq.Field + ":" + query.Quote(fq.Value)
+}
+
 func (fq filterQuery) toQueryString() string {
+	if q, ok := parseRange(fq.Value); ok {
+		// field:[from TO to], e.g. year:[2000 TO 2010}
+		return fmt.Sprintf("%s:%s", fq.Field, url.QueryEscape(rangeFilterValue(q)))
+	}
 	// field:value, e.g. subject:"abc+xyz"
-	return fmt.Sprintf("%s:%s", fq.Field, url.QueryEscape("\""+fq.Value+"\""))
+	// (quotes and backslashes in the value are escaped)
+	return fmt.Sprintf("%s:%s", fq.Field, url.QueryEscape(query.Quote(fq.Value)))
+}
+
+// equalValue returns true if the filter is for the value indicated.
+// Ranges are considered equal if they are equivalent (e.g. "[0 TO 10}"
+// and "[0  TO  10 }")
+func (fq filterQuery) equalValue(value string) bool {
+	if fq.Value == value {
+		return true
+	}
+	q1, ok1 := parseRange(fq.Value)
+	q2, ok2 := parseRange(value)
+	return ok1 && ok2 && q1 == q2
 }
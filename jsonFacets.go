@@ -0,0 +1,267 @@
+package solr
+
+import (
+	"encoding/json"
+	"sort"
+)
+
+// JSONFacets represents the facets requested via Solr's JSON Facet
+// API (the json.facet parameter) keyed by the name of each facet.
+type JSONFacets map[string]JSONFacet
+
+// JSONFacet represents a single facet (or aggregation function) in
+// Solr's JSON Facet API. Use TermsFacet(), QueryFacet(), RangeFacet()
+// and Aggregation() to create them, for example:
+//
+// 	params.JSONFacets = solr.JSONFacets{
+// 		"authors": solr.TermsFacet("author_s").
+// 			WithLimit(5).
+// 			WithSubFacet("avgPrice", solr.Aggregation("avg(price_f)")),
+// 		"totalPrice": solr.Aggregation("sum(price_f)"),
+// 	}
+type JSONFacet struct {
+	Type     string // terms, query, or range (empty for aggregations)
+	Function string // Aggregation function, e.g. sum(price_f), unique(author_s), percentile(price_f,50)
+
+	Field      string // terms and range facets
+	Query      string // query facets
+	Limit      *int // nil to use Solr's default (see Int())
+	Offset     *int
+	MinCount   *int
+	Sort       string // e.g. "count desc" or "avgPrice desc"
+	Missing    bool
+	NumBuckets bool
+	AllBuckets bool
+	Start      string // range facets
+	End        string // range facets
+	Gap        string // range facets
+
+	Domain *JSONFacetDomain
+	Facets JSONFacets // Sub-facets and aggregations calculated for each bucket
+}
+
+// JSONFacetDomain changes the set of documents a facet is calculated on.
+type JSONFacetDomain struct {
+	ExcludeTags   []string `json:"excludeTags,omitempty"`
+	Filter        []string `json:"filter,omitempty"`
+	Query         []string `json:"query,omitempty"`
+	BlockParent   string   `json:"blockParent,omitempty"`
+	BlockChildren string   `json:"blockChildren,omitempty"`
+}
+
+// TermsFacet creates a terms facet on a field.
+func TermsFacet(field string) JSONFacet {
+	return JSONFacet{Type: "terms", Field: field}
+}
+
+// QueryFacet creates a facet that counts the documents matching a query.
+func QueryFacet(q string) JSONFacet {
+	return JSONFacet{Type: "query", Query: q}
+}
+
+// RangeFacet creates a range facet on a numeric or date field.
+func RangeFacet(field, start, end, gap string) JSONFacet {
+	return JSONFacet{Type: "range", Field: field, Start: start, End: end, Gap: gap}
+}
+
+// Aggregation creates an aggregation function, e.g. "sum(price_f)",
+// "avg(price_f)", "unique(author_s)", or "percentile(price_f,50,90)".
+func Aggregation(function string) JSONFacet {
+	return JSONFacet{Function: function}
+}
+
+// Int returns a pointer to the value provided. Useful to set the
+// Limit, Offset, and MinCount of a JSONFacet.
+func Int(value int) *int {
+	return &value
+}
+
+// WithLimit returns a copy of the facet with the limit indicated.
+func (f JSONFacet) WithLimit(limit int) JSONFacet {
+	f.Limit = Int(limit)
+	return f
+}
+
+// WithMinCount returns a copy of the facet with the mincount
+// indicated (e.g. 0 to include the buckets without documents.)
+func (f JSONFacet) WithMinCount(minCount int) JSONFacet {
+	f.MinCount = Int(minCount)
+	return f
+}
+
+// WithSort returns a copy of the facet with the sort indicated.
+func (f JSONFacet) WithSort(sort string) JSONFacet {
+	f.Sort = sort
+	return f
+}
+
+// WithExcludeTags returns a copy of the facet that excludes the filters
+// tagged with the tags indicated (e.g. for multi-select facets).
+func (f JSONFacet) WithExcludeTags(tags ...string) JSONFacet {
+	domain := JSONFacetDomain{}
+	if f.Domain != nil {
+		domain = *f.Domain
+	}
+	domain.ExcludeTags = append(append([]string{}, domain.ExcludeTags...), tags...)
+	f.Domain = &domain
+	return f
+}
+
+// WithSubFacet returns a copy of the facet with a sub-facet (or
+// aggregation) calculated for each of its buckets.
+func (f JSONFacet) WithSubFacet(name string, sub JSONFacet) JSONFacet {
+	facets := JSONFacets{}
+	for k, v := range f.Facets {
+		facets[k] = v
+	}
+	facets[name] = sub
+	f.Facets = facets
+	return f
+}
+
+// MarshalJSON encodes the facet in the format expected by Solr.
+func (f JSONFacet) MarshalJSON() ([]byte, error) {
+	if f.Type == "" {
+		return json.Marshal(f.Function)
+	}
+
+	data := map[string]interface{}{"type": f.Type}
+	addString := func(key, value string) {
+		if value != "" {
+			data[key] = value
+		}
+	}
+	addInt := func(key string, value *int) {
+		if value != nil {
+			data[key] = *value
+		}
+	}
+	addBool := func(key string, value bool) {
+		if value {
+			data[key] = value
+		}
+	}
+	addString("field", f.Field)
+	addString("q", f.Query)
+	addInt("limit", f.Limit)
+	addInt("offset", f.Offset)
+	addInt("mincount", f.MinCount)
+	addString("sort", f.Sort)
+	addBool("missing", f.Missing)
+	addBool("numBuckets", f.NumBuckets)
+	addBool("allBuckets", f.AllBuckets)
+	addString("start", f.Start)
+	addString("end", f.End)
+	addString("gap", f.Gap)
+	if f.Domain != nil {
+		data["domain"] = f.Domain
+	}
+	if len(f.Facets) > 0 {
+		data["facet"] = f.Facets
+	}
+	return json.Marshal(data)
+}
+
+func (facets JSONFacets) toQueryString() string {
+	if len(facets) == 0 {
+		return ""
+	}
+	// JSONFacet values always marshal (they only contain
+	// strings, numbers, and booleans)
+	bytes, _ := json.Marshal(facets)
+	return qsAdd("json.facet", string(bytes))
+}
+
+// JSONFacetResult represents the results of the JSON Facet API. The
+// root of the results (SearchResponse.JSONFacets), each bucket of a
+// terms or range facet, and the results of a query facet are all
+// represented by a JSONFacetResult.
+type JSONFacetResult struct {
+	Value      interface{}                // Value of the bucket (nil for the root and query facets)
+	Count      int                        // Number of documents in the bucket
+	Metrics    map[string]interface{}     // Results of the aggregation functions
+	Facets     map[string]JSONFacetResult // Results of the sub-facets
+	Buckets    []JSONFacetResult          // Buckets (for terms and range facets)
+	NumBuckets int                        // When requested via NumBuckets
+	Missing    *JSONFacetResult           // When requested via Missing
+	AllBuckets *JSONFacetResult           // When requested via AllBuckets
+}
+
+// Facet returns the results of a sub-facet.
+func (r JSONFacetResult) Facet(name string) (JSONFacetResult, bool) {
+	facet, ok := r.Facets[name]
+	return facet, ok
+}
+
+// Metric returns the result of an aggregation function as a float
+// (0 if not found or the value is not a number, e.g. for percentile
+// with many values use Metrics instead.)
+func (r JSONFacetResult) Metric(name string) float64 {
+	value, _ := convertFloat64(r.Metrics[name])
+	return value
+}
+
+// ValueString returns the value of the bucket as a string.
+func (r JSONFacetResult) ValueString() string {
+	if r.Value == nil {
+		return ""
+	}
+	return convertString(r.Value)
+}
+
+// newJSONFacetResult parses the "facets" section of the response
+// from Solr.
+func newJSONFacetResult(raw map[string]interface{}) JSONFacetResult {
+	r := JSONFacetResult{
+		Metrics: map[string]interface{}{},
+		Facets:  map[string]JSONFacetResult{},
+	}
+
+	// Sort the keys so that the results are deterministic.
+	keys := []string{}
+	for key := range raw {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		value := raw[key]
+		switch key {
+		case "val":
+			r.Value = value
+			continue
+		case "count":
+			count, _ := convertInt64(value)
+			r.Count = int(count)
+			continue
+		case "numBuckets":
+			count, _ := convertInt64(value)
+			r.NumBuckets = int(count)
+			continue
+		case "buckets":
+			for _, bucket := range toSlice(value) {
+				if data, ok := bucket.(map[string]interface{}); ok {
+					r.Buckets = append(r.Buckets, newJSONFacetResult(data))
+				}
+			}
+			continue
+		case "missing", "allBuckets":
+			if data, ok := value.(map[string]interface{}); ok {
+				result := newJSONFacetResult(data)
+				if key == "missing" {
+					r.Missing = &result
+				} else {
+					r.AllBuckets = &result
+				}
+				continue
+			}
+		}
+
+		if data, ok := value.(map[string]interface{}); ok {
+			r.Facets[key] = newJSONFacetResult(data)
+		} else {
+			r.Metrics[key] = value
+		}
+	}
+	return r
+}
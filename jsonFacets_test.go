@@ -0,0 +1,69 @@
+package solr
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestJSONFacetsRequest(t *testing.T) {
+	facets := JSONFacets{
+		"authors": TermsFacet("author_s").
+			WithLimit(5).
+			WithExcludeTags("auth").
+			WithSubFacet("avgPrice", Aggregation("avg(price_f)")),
+		"total": Aggregation("sum(price_f)"),
+	}
+	bytes, err := json.Marshal(facets)
+	if err != nil {
+		t.Fatalf("Marshal error: %s", err)
+	}
+
+	expected := `{"authors":{"domain":{"excludeTags":["auth"]},"facet":{"avgPrice":"avg(price_f)"},"field":"author_s","limit":5,"type":"terms"},"total":"sum(price_f)"}`
+	if string(bytes) != expected {
+		t.Errorf("Unexpected json.facet: %s", bytes)
+	}
+
+	zeros := TermsFacet("author_s").WithLimit(0).WithMinCount(0)
+	zeros.Offset = Int(0)
+	bytes, _ = json.Marshal(zeros)
+	expected = `{"field":"author_s","limit":0,"mincount":0,"offset":0,"type":"terms"}`
+	if string(bytes) != expected {
+		t.Errorf("Unexpected json.facet with zero values: %s", bytes)
+	}
+}
+
+func TestJSONFacetsResponse(t *testing.T) {
+	raw, err := NewResponseRaw([]byte(`{"response":{"numFound":3,"start":0,"docs":[]},
+		"facets":{
+			"count":3,
+			"total":60.5,
+			"online":{"count":1},
+			"authors":{"numBuckets":2,"buckets":[
+				{"val":"ada","count":2,"avgPrice":20.25,
+					"years":{"buckets":[{"val":1843,"count":2}]}},
+				{"val":"grace","count":1,"avgPrice":20}]}}}`))
+	if err != nil {
+		t.Fatalf("NewResponseRaw error: %s", err)
+	}
+
+	params := NewSearchParams("*", map[string]string{}, map[string]string{})
+	r := newSearchResponse(params, raw)
+	root := r.JSONFacets
+	if root.Count != 3 || root.Metric("total") != 60.5 || root.Facets["online"].Count != 1 {
+		t.Errorf("Unexpected root facet: %#v", root)
+	}
+
+	authors, _ := root.Facet("authors")
+	if authors.NumBuckets != 2 || len(authors.Buckets) != 2 {
+		t.Fatalf("Unexpected authors facet: %#v", authors)
+	}
+
+	ada := authors.Buckets[0]
+	if ada.ValueString() != "ada" || ada.Count != 2 || ada.Metric("avgPrice") != 20.25 {
+		t.Errorf("Unexpected bucket: %#v", ada)
+	}
+
+	if years := ada.Facets["years"]; len(years.Buckets) != 1 || years.Buckets[0].ValueString() != "1843" {
+		t.Errorf("Unexpected sub-facet: %#v", years)
+	}
+}
@@ -0,0 +1,120 @@
+package solr
+
+import (
+	"crypto/tls"
+	"net/http"
+	"time"
+)
+
+// Option configures a Solr instance. Options are passed to New().
+type Option func(*Solr)
+
+// WithHTTPClient uses the provided client for all the HTTP requests
+// to Solr instead of http.DefaultClient.
+func WithHTTPClient(client *http.Client) Option {
+	return func(s *Solr) {
+		if client != nil {
+			s.httpClient = client
+		}
+	}
+}
+
+// WithTimeout sets the time limit for each HTTP request to Solr
+// (see http.Client.Timeout).
+func WithTimeout(timeout time.Duration) Option {
+	return func(s *Solr) {
+		client := s.clientCopy()
+		client.Timeout = timeout
+		s.httpClient = client
+	}
+}
+
+// WithTLSConfig sets the TLS configuration (e.g. custom root CAs or
+// client certificates) used when connecting to Solr via HTTPS.
+//
+// The configuration is only applied when the client uses the default
+// transport or an *http.Transport. Custom RoundTrippers (e.g. set via
+// WithTransport) are left untouched and must configure TLS themselves.
+func WithTLSConfig(config *tls.Config) Option {
+	return func(s *Solr) {
+		client := s.clientCopy()
+		transport, ok := newTransport(client.Transport)
+		if !ok {
+			return
+		}
+		transport.TLSClientConfig = config
+		client.Transport = transport
+		s.httpClient = client
+	}
+}
+
+// WithTransport sets the http.RoundTripper used for the HTTP requests
+// to Solr. Use it to configure connection pool sizes, proxies, etc.
+func WithTransport(transport http.RoundTripper) Option {
+	return func(s *Solr) {
+		client := s.clientCopy()
+		client.Transport = transport
+		s.httpClient = client
+	}
+}
+
+// WithUserAgent sets the User-Agent header sent to Solr.
+func WithUserAgent(userAgent string) Option {
+	return func(s *Solr) {
+		s.userAgent = userAgent
+	}
+}
+
+// WithCredentials sets the credentials used to authenticate each
+// HTTP request to Solr (see BasicAuth, BearerToken, RefreshingToken).
+func WithCredentials(credentials Credentials) Option {
+	return func(s *Solr) {
+		s.credentials = credentials
+	}
+}
+
+// WithUpdateOptions sets the options used by the update methods
+// (e.g. PostDocs, Delete) that do not receive an UpdateOptions
+// parameter. By default DefaultUpdateOptions() is used.
+func WithUpdateOptions(opts UpdateOptions) Option {
+	return func(s *Solr) {
+		s.updateOpts = &opts
+	}
+}
+
+// clientCopy returns a shallow copy of the HTTP client currently
+// configured so that options never modify a client that might be
+// shared (e.g. http.DefaultClient).
+func (s Solr) clientCopy() *http.Client {
+	client := *s.client()
+	return &client
+}
+
+// updateOptions returns the UpdateOptions to use when none are given.
+func (s Solr) updateOptions() UpdateOptions {
+	if s.updateOpts == nil {
+		return DefaultUpdateOptions()
+	}
+	return *s.updateOpts
+}
+
+// client returns the HTTP client to use for the requests to Solr.
+func (s Solr) client() *http.Client {
+	if s.httpClient == nil {
+		return http.DefaultClient
+	}
+	return s.httpClient
+}
+
+// newTransport returns a copy of the transport provided if it is an
+// *http.Transport, or a copy of http.DefaultTransport if it is nil.
+// Returns false for any other http.RoundTripper.
+func newTransport(rt http.RoundTripper) (*http.Transport, bool) {
+	if rt == nil {
+		return http.DefaultTransport.(*http.Transport).Clone(), true
+	}
+	if transport, ok := rt.(*http.Transport); ok {
+		return transport.Clone(), true
+	}
+	return nil, false
+}
@@ -0,0 +1,95 @@
+package solr
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestOptions(t *testing.T) {
+	solr := New("http://localhost", false, WithTimeout(5*time.Second))
+	if solr.client() == http.DefaultClient {
+		t.Errorf("WithTimeout modified http.DefaultClient")
+	}
+	if solr.client().Timeout != 5*time.Second || http.DefaultClient.Timeout != 0 {
+		t.Errorf("Unexpected timeout: %v", solr.client().Timeout)
+	}
+
+	solr = New("http://localhost", false)
+	if solr.client() != http.DefaultClient {
+		t.Errorf("Expected http.DefaultClient by default")
+	}
+}
+
+func TestUserAgent(t *testing.T) {
+	userAgent := ""
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		userAgent = r.Header.Get("User-Agent")
+		w.Write([]byte(`{"responseHeader":{"status":0}}`))
+	}))
+	defer server.Close()
+
+	solr := New(server.URL, false, WithUserAgent("my-app/1.0"))
+	if _, err := solr.SearchText("hello"); err != nil {
+		t.Errorf("Search error: %s", err)
+	}
+	if userAgent != "my-app/1.0" {
+		t.Errorf("Unexpected User-Agent: %s", userAgent)
+	}
+}
+
+type countingTransport struct {
+	count int
+}
+
+func (t *countingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.count++
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestTLSConfig(t *testing.T) {
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"responseHeader":{"status":0}}`))
+	}))
+	server.Config.ErrorLog = log.New(ioutil.Discard, "", 0)
+	server.StartTLS()
+	defer server.Close()
+
+	if _, err := New(server.URL, false).SearchText("hello"); err == nil {
+		t.Errorf("Expected a certificate error without the TLS config")
+	}
+
+	roots := x509.NewCertPool()
+	roots.AddCert(server.Certificate())
+	solr := New(server.URL, false, WithTLSConfig(&tls.Config{RootCAs: roots}))
+	if _, err := solr.SearchText("hello"); err != nil {
+		t.Errorf("Search error: %s", err)
+	}
+	if config := http.DefaultTransport.(*http.Transport).TLSClientConfig; config != nil && config.RootCAs == roots {
+		t.Errorf("WithTLSConfig modified http.DefaultTransport")
+	}
+}
+
+func TestTransport(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"responseHeader":{"status":0}}`))
+	}))
+	defer server.Close()
+
+	transport := &countingTransport{}
+	solr := New(server.URL, false, WithTransport(transport), WithTLSConfig(&tls.Config{}))
+	if solr.client().Transport != transport {
+		t.Errorf("WithTLSConfig replaced the custom transport")
+	}
+	if _, err := solr.SearchText("hello"); err != nil {
+		t.Errorf("Search error: %s", err)
+	}
+	if transport.count != 1 {
+		t.Errorf("Unexpected number of requests via the transport: %d", transport.count)
+	}
+}
@@ -0,0 +1,153 @@
+package solr
+
+import (
+	"net/url"
+	"strings"
+)
+
+// PivotFacets is an array of PivotFacet definitions.
+type PivotFacets []PivotFacet
+
+// PivotFacet represents a pivot (hierarchical) facet, for example
+// format → language → subject. When returned in a SearchResponse
+// Nodes has the values (and counts) for the first field with the
+// values for the next field as their children.
+type PivotFacet struct {
+	Fields []string    // Names of the fields in Solr (in the order to pivot)
+	Title  string      // Display title for the facet
+	Nodes  []PivotNode // Values returned by Solr for the first field
+}
+
+// PivotNode represents a value in a pivot facet. As with FacetValue,
+// AddUrl and RemoveUrl are set via SetAddRemoveUrls().
+type PivotNode struct {
+	Field     string      // Name of the field in Solr
+	Value     string      // Value returned by Solr for this field
+	Count     int         // Number of documents that matched this field/value
+	Active    bool        // true if we are filtering by this value
+	AddUrl    string      // URL to filter by this value (and its parents)
+	RemoveUrl string      // URL to remove the filter by this value (and its children)
+	Children  []PivotNode // Values for the next field in the pivot
+}
+
+// NewPivotFacet creates the definition of a pivot facet.
+func NewPivotFacet(title string, fields ...string) PivotFacet {
+	return PivotFacet{Fields: fields, Title: title}
+}
+
+func (p PivotFacet) key() string {
+	return strings.Join(p.Fields, ",")
+}
+
+func (pivots PivotFacets) toQueryString() string {
+	qs := ""
+	for _, p := range pivots {
+		qs += qsAdd("facet.pivot", p.key())
+	}
+	return qs
+}
+
+// Sets the AddUrl and RemoveUrl of all the nodes in the pivot facets
+// using the provided baseUrl (see Facets.SetAddRemoveUrls)
+//
+// The AddUrl of a node also filters by its parent nodes (e.g. clicking
+// on "English" under "Book" filters by format and language) and the
+// RemoveUrl of a node also removes the filter of its children.
+func (pivots PivotFacets) SetAddRemoveUrls(baseUrl string) {
+	for _, p := range pivots {
+		setPivotNodeUrls(p.Nodes, baseUrl, nil)
+	}
+}
+
+func setPivotNodeUrls(nodes []PivotNode, baseUrl string, parents []string) {
+	for i, node := range nodes {
+		fqValEncoded := pivotFq(node)
+		path := append(append([]string{}, parents...), fqValEncoded)
+
+		addUrl := baseUrl
+		for _, fq := range path {
+			if !strings.Contains(addUrl, fq) {
+				addUrl += "&" + fq
+			}
+		}
+		nodes[i].AddUrl = addUrl
+
+		removeUrl := strings.Replace(baseUrl, fqValEncoded, "", 1)
+		for _, fq := range node.activeDescendants() {
+			removeUrl = strings.Replace(removeUrl, fq, "", 1)
+		}
+		nodes[i].RemoveUrl = removeUrl
+
+		setPivotNodeUrls(nodes[i].Children, baseUrl, path)
+	}
+}
+
+// pivotFq returns the encoded filter for the node as it appears
+// in the URLs (e.g. "fq=format|Book&")
+func pivotFq(node PivotNode) string {
+	return "fq=" + node.Field + "|" + url.QueryEscape(node.Value) + "&"
+}
+
+func (node PivotNode) activeDescendants() []string {
+	fqs := []string{}
+	for _, child := range node.Children {
+		if child.Active {
+			fqs = append(fqs, pivotFq(child))
+		}
+		fqs = append(fqs, child.activeDescendants()...)
+	}
+	return fqs
+}
+
+// activeFilters returns the filters of all the active nodes.
+func (pivots PivotFacets) activeFilters() filterQueries {
+	fqs := filterQueries{}
+	for _, p := range pivots {
+		fqs = append(fqs, activeNodeFilters(p.Nodes)...)
+	}
+	return fqs
+}
+
+func activeNodeFilters(nodes []PivotNode) filterQueries {
+	fqs := filterQueries{}
+	for _, node := range nodes {
+		if node.Active {
+			fqs = append(fqs, filterQuery{Field: node.Field, Value: node.Value})
+		}
+		fqs = append(fqs, activeNodeFilters(node.Children)...)
+	}
+	return fqs
+}
+
+// Creates the pivot facets from the raw data from Solr in the order
+// of the definitions.
+func newPivotFacets(defs PivotFacets, raw map[string][]pivotRaw, fqs filterQueries) PivotFacets {
+	pivots := PivotFacets{}
+	for _, def := range defs {
+		rawNodes, found := raw[def.key()]
+		if !found {
+			continue
+		}
+		pivot := PivotFacet{Fields: def.Fields, Title: def.Title}
+		pivot.Nodes = newPivotNodes(rawNodes, fqs)
+		pivots = append(pivots, pivot)
+	}
+	return pivots
+}
+
+func newPivotNodes(rawNodes []pivotRaw, fqs filterQueries) []PivotNode {
+	nodes := []PivotNode{}
+	for _, rawNode := range rawNodes {
+		value := convertString(rawNode.Value)
+		count, _ := convertInt64(rawNode.Count)
+		node := PivotNode{
+			Field:    rawNode.Field,
+			Value:    value,
+			Count:    int(count),
+			Active:   fqs.HasFieldValue(rawNode.Field, value),
+			Children: newPivotNodes(rawNode.Pivot, fqs),
+		}
+		nodes = append(nodes, node)
+	}
+	return nodes
+}
@@ -0,0 +1,54 @@
+package solr
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestPivotFacets(t *testing.T) {
+	raw, err := NewResponseRaw([]byte(`{"response":{"numFound":3,"start":0,"docs":[]},
+		"facet_counts":{"facet_pivot":{"format,language":[
+			{"field":"format","value":"Book","count":3,"pivot":[
+				{"field":"language","value":"English","count":2},
+				{"field":"language","value":"Spanish","count":1}]},
+			{"field":"format","value":"Map","count":1}]}}}`))
+	if err != nil {
+		t.Fatalf("NewResponseRaw error: %s", err)
+	}
+
+	qs := url.Values{"fq": []string{"format|Book"}}
+	params := NewSearchParamsFromQs(qs, map[string]string{}, map[string]string{})
+	params.PivotFacets = PivotFacets{NewPivotFacet("Format", "format", "language")}
+	if s := params.toSolrQueryString(); !strings.Contains(s, "facet=on&facet.pivot=format%2Clanguage&") {
+		t.Errorf("Unexpected SearchParams URL: %s", s)
+	}
+
+	r := newSearchResponse(params, raw)
+	if len(r.PivotFacets) != 1 || len(r.PivotFacets[0].Nodes) != 2 {
+		t.Fatalf("Unexpected pivot facets: %#v", r.PivotFacets)
+	}
+
+	book := r.PivotFacets[0].Nodes[0]
+	if book.Value != "Book" || book.Count != 3 || !book.Active || len(book.Children) != 2 || book.Children[0].Active {
+		t.Errorf("Unexpected pivot node: %#v", book)
+	}
+
+	if r.Url != "q=%2A&fq=format|Book&" {
+		t.Errorf("Unexpected Url: %s", r.Url)
+	}
+
+	r.PivotFacets.SetAddRemoveUrls(r.Url)
+	book = r.PivotFacets[0].Nodes[0]
+	if book.RemoveUrl != "q=%2A&" {
+		t.Errorf("Unexpected RemoveUrl: %s", book.RemoveUrl)
+	}
+
+	if spanish := book.Children[1]; spanish.AddUrl != "q=%2A&fq=format|Book&&fq=language|Spanish&" {
+		t.Errorf("Unexpected AddUrl: %s", spanish.AddUrl)
+	}
+
+	if m := r.PivotFacets[0].Nodes[1]; m.AddUrl != "q=%2A&fq=format|Book&&fq=format|Map&" {
+		t.Errorf("Unexpected AddUrl: %s", m.AddUrl)
+	}
+}
@@ -0,0 +1,336 @@
+// Package query provides types to build Solr queries (standard Lucene
+// and edismax syntax) with the values correctly escaped. For example:
+//
+// 	q := query.Bool().
+// 		Must(query.Phrase("title", `the "best" book`)).
+// 		MustNot(query.Term("format", "e-book"))
+// 	params.Q = q.String()
+//
+// produces +title:"the \"best\" book" -format:e\-book
+package query
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Query is implemented by all the query types in this package.
+type Query interface {
+	// String renders the query in Lucene syntax.
+	String() string
+}
+
+// Raw is a query string used as-is (i.e. not escaped).
+type Raw string
+
+func (q Raw) String() string {
+	return string(q)
+}
+
+// TermQuery matches a single term in a field.
+type TermQuery struct {
+	Field string
+	Value string
+}
+
+// Term creates a query for a single term, e.g. subject:geography.
+// The field can be empty to search the default field(s).
+func Term(field, value string) TermQuery {
+	return TermQuery{Field: field, Value: value}
+}
+
+func (q TermQuery) String() string {
+	return withField(q.Field, Escape(q.Value))
+}
+
+// PhraseQuery matches an exact phrase in a field.
+type PhraseQuery struct {
+	Field string
+	Text  string
+	Slop  int // Maximum distance between the terms (0 for an exact match)
+}
+
+// Phrase creates a phrase query, e.g. title:"one two".
+func Phrase(field, text string) PhraseQuery {
+	return PhraseQuery{Field: field, Text: text}
+}
+
+func (q PhraseQuery) String() string {
+	s := withField(q.Field, Quote(q.Text))
+	if q.Slop > 0 {
+		s += fmt.Sprintf("~%d", q.Slop)
+	}
+	return s
+}
+
+// RangeQuery matches the values in a field between From and To.
+type RangeQuery struct {
+	Field       string
+	From        string // Use "" or "*" for an open range
+	To          string // Use "" or "*" for an open range
+	ExcludeFrom bool
+	ExcludeTo   bool
+}
+
+// Range creates an inclusive range query, e.g. year:[2000 TO 2010].
+// Use "" or "*" for open ranges. Date math (e.g. NOW-30DAYS) is
+// passed through.
+func Range(field, from, to string) RangeQuery {
+	return RangeQuery{Field: field, From: from, To: to}
+}
+
+func (q RangeQuery) String() string {
+	open, close := "[", "]"
+	if q.ExcludeFrom {
+		open = "{"
+	}
+	if q.ExcludeTo {
+		close = "}"
+	}
+	return withField(q.Field, open+rangeValue(q.From)+" TO "+rangeValue(q.To)+close)
+}
+
+// rangeValue escapes the characters that would break the range
+// syntax, other characters are left as-is so that date math
+// (e.g. NOW-1YEAR/DAY) and negative numbers work.
+func rangeValue(value string) string {
+	if value == "" || value == "*" {
+		return "*"
+	}
+	return escapeChars(value, `\ []{}"`)
+}
+
+// WildcardQuery matches the terms in a field against a pattern.
+type WildcardQuery struct {
+	Field   string
+	Pattern string
+}
+
+// Wildcard creates a wildcard query, e.g. title:geo*. Only * and ?
+// in the pattern are treated as wildcards, everything else is escaped.
+func Wildcard(field, pattern string) WildcardQuery {
+	return WildcardQuery{Field: field, Pattern: pattern}
+}
+
+func (q WildcardQuery) String() string {
+	var b strings.Builder
+	for _, r := range q.Pattern {
+		if r == '*' || r == '?' {
+			b.WriteRune(r)
+		} else {
+			b.WriteString(Escape(string(r)))
+		}
+	}
+	return withField(q.Field, b.String())
+}
+
+// FuzzyQuery matches terms similar to a term.
+type FuzzyQuery struct {
+	Field    string
+	Value    string
+	Distance int // Maximum edit distance (0 uses Solr's default of 2)
+}
+
+// Fuzzy creates a fuzzy query, e.g. title:roam~1.
+func Fuzzy(field, value string, distance int) FuzzyQuery {
+	return FuzzyQuery{Field: field, Value: value, Distance: distance}
+}
+
+func (q FuzzyQuery) String() string {
+	s := withField(q.Field, Escape(q.Value)) + "~"
+	if q.Distance > 0 {
+		s += strconv.Itoa(q.Distance)
+	}
+	return s
+}
+
+// ExistsQuery matches the documents that have a value in a field.
+type ExistsQuery struct {
+	Field string
+}
+
+// Exists creates a query that matches the documents with any
+// value in the field, e.g. title:[* TO *].
+func Exists(field string) ExistsQuery {
+	return ExistsQuery{Field: field}
+}
+
+func (q ExistsQuery) String() string {
+	return withField(q.Field, "[* TO *]")
+}
+
+// BoostQuery boosts the score of the documents that match a query.
+type BoostQuery struct {
+	Query Query
+	Boost float64
+}
+
+// Boost creates a boosted query, e.g. (title:geography)^2.
+func Boost(q Query, boost float64) BoostQuery {
+	return BoostQuery{Query: q, Boost: boost}
+}
+
+func (q BoostQuery) String() string {
+	return "(" + q.Query.String() + ")^" + strconv.FormatFloat(q.Boost, 'f', -1, 64)
+}
+
+// BoolQuery combines several queries.
+type BoolQuery struct {
+	MustClauses    []Query
+	ShouldClauses  []Query
+	MustNotClauses []Query
+}
+
+// Bool creates an empty boolean query, use Must(), Should() and
+// MustNot() to add clauses to it.
+func Bool() BoolQuery {
+	return BoolQuery{}
+}
+
+// Must adds clauses that must match.
+func (q BoolQuery) Must(queries ...Query) BoolQuery {
+	q.MustClauses = appendQueries(q.MustClauses, queries)
+	return q
+}
+
+// Should adds clauses that should match. When the query has no Must
+// clauses at least one of the Should clauses must match.
+func (q BoolQuery) Should(queries ...Query) BoolQuery {
+	q.ShouldClauses = appendQueries(q.ShouldClauses, queries)
+	return q
+}
+
+// MustNot adds clauses that must not match.
+func (q BoolQuery) MustNot(queries ...Query) BoolQuery {
+	q.MustNotClauses = appendQueries(q.MustNotClauses, queries)
+	return q
+}
+
+func (q BoolQuery) String() string {
+	clauses := []string{}
+	for _, must := range q.MustClauses {
+		clauses = append(clauses, "+"+group(must))
+	}
+	for _, should := range q.ShouldClauses {
+		clauses = append(clauses, group(should))
+	}
+	if len(clauses) == 0 && len(q.MustNotClauses) > 0 {
+		// A purely negative query matches nothing in Lucene
+		clauses = append(clauses, "*:*")
+	}
+	for _, mustNot := range q.MustNotClauses {
+		clauses = append(clauses, "-"+group(mustNot))
+	}
+	return strings.Join(clauses, " ")
+}
+
+// LocalParamsQuery prefixes a query with local params, e.g.
+// {!parent which="type:book"}title:geography
+type LocalParamsQuery struct {
+	Type   string            // Query parser (e.g. "parent", "edismax", "tag")
+	Params map[string]string // Values are quoted and escaped when rendered
+	Query  Query             // Can be nil (e.g. when used with facet.field)
+}
+
+// LocalParams creates a query with local params for the query parser
+// indicated (use "" to only pass params, e.g. {!tag=x}).
+func LocalParams(parser string, q Query) LocalParamsQuery {
+	return LocalParamsQuery{Type: parser, Params: map[string]string{}, Query: q}
+}
+
+// With adds a parameter to the local params.
+func (q LocalParamsQuery) With(key, value string) LocalParamsQuery {
+	params := map[string]string{}
+	for k, v := range q.Params {
+		params[k] = v
+	}
+	params[key] = value
+	q.Params = params
+	return q
+}
+
+func (q LocalParamsQuery) String() string {
+	tokens := []string{}
+	if q.Type != "" {
+		tokens = append(tokens, q.Type)
+	}
+	keys := []string{}
+	for key := range q.Params {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		tokens = append(tokens, key+"="+LocalParamValue(q.Params[key]))
+	}
+	s := "{!" + strings.Join(tokens, " ") + "}"
+	if q.Query != nil {
+		s += q.Query.String()
+	}
+	return s
+}
+
+// Escape escapes the Lucene special characters (and whitespace) in
+// a value so that it is searched as a single term.
+func Escape(value string) string {
+	return escapeChars(value, "\\+-!():^[]\"{}~*?|&;/ \t\n\r")
+}
+
+func escapeChars(value, chars string) string {
+	var b strings.Builder
+	for _, r := range value {
+		if strings.ContainsRune(chars, r) {
+			b.WriteRune('\\')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// Quote returns the text as a quoted phrase (escaping any quotes and
+// backslashes in it.)
+func Quote(text string) string {
+	text = strings.Replace(text, `\`, `\\`, -1)
+	text = strings.Replace(text, `"`, `\"`, -1)
+	return `"` + text + `"`
+}
+
+// LocalParamValue returns a value to use in local params, the value
+// is quoted unless it is a simple word (e.g. tag=books)
+func LocalParamValue(value string) string {
+	if value == "" || strings.IndexFunc(value, isSpecial) != -1 {
+		return Quote(value)
+	}
+	return value
+}
+
+func isSpecial(r rune) bool {
+	return !(unicode.IsLetter(r) || unicode.IsDigit(r) || strings.ContainsRune("_-.,", r))
+}
+
+// appendQueries returns a new slice so that builders derived from
+// the same BoolQuery do not share clauses.
+func appendQueries(clauses, queries []Query) []Query {
+	result := make([]Query, 0, len(clauses)+len(queries))
+	result = append(result, clauses...)
+	return append(result, queries...)
+}
+
+func withField(field, value string) string {
+	if field == "" {
+		return value
+	}
+	return field + ":" + value
+}
+
+// group wraps compound queries in parenthesis.
+func group(q Query) string {
+	s := q.String()
+	switch q.(type) {
+	case BoolQuery, Raw:
+		return "(" + s + ")"
+	}
+	return s
+}
@@ -0,0 +1,47 @@
+package query
+
+import "testing"
+
+func TestEscape(t *testing.T) {
+	if s := Term("title", `a:b "c" (d)`).String(); s != `title:a\:b\ \"c\"\ \(d\)` {
+		t.Errorf("Unexpected term: %s", s)
+	}
+
+	if s := Phrase("title", `say "hi" \o/`).String(); s != `title:"say \"hi\" \\o/"` {
+		t.Errorf("Unexpected phrase: %s", s)
+	}
+
+	if s := Wildcard("title", "geo* (x)?").String(); s != `title:geo*\ \(x\)?` {
+		t.Errorf("Unexpected wildcard: %s", s)
+	}
+}
+
+func TestQueries(t *testing.T) {
+	tests := map[string]Query{
+		`year:[2000 TO *]`:                   Range("year", "2000", ""),
+		`year:{2000 TO 2010]`:                RangeQuery{Field: "year", From: "2000", To: "2010", ExcludeFrom: true},
+		`date:[NOW-30DAYS/DAY TO NOW]`:       Range("date", "NOW-30DAYS/DAY", "NOW"),
+		`title:roam~1`:                       Fuzzy("title", "roam", 1),
+		`isbn:[* TO *]`:                      Exists("isbn"),
+		`(title:go)^2.5`:                     Boost(Term("title", "go"), 2.5),
+		`{!tag=fmt}format:"book"`:            LocalParams("", Phrase("format", "book")).With("tag", "fmt"),
+		`{!parent which="type:book"}id:1`:    LocalParams("parent", Raw("id:1")).With("which", "type:book"),
+		`*:* -format:ebook`:                  Bool().MustNot(Term("format", "ebook")),
+		`+title:go (a:1 b:2) -c:3`:           Bool().Must(Term("title", "go")).Should(Bool().Should(Term("a", "1"), Term("b", "2"))).MustNot(Term("c", "3")),
+		`+(title:go OR title:rust) author:x`: Bool().Must(Raw("title:go OR title:rust")).Should(Term("author", "x")),
+	}
+	for expected, q := range tests {
+		if s := q.String(); s != expected {
+			t.Errorf("Expected %s, got %s", expected, s)
+		}
+	}
+}
+
+func TestBoolImmutable(t *testing.T) {
+	base := Bool().Must(Term("a", "1"))
+	q1 := base.Must(Term("b", "2"))
+	q2 := base.Must(Term("c", "3"))
+	if q1.String() != "+a:1 +b:2" || q2.String() != "+a:1 +c:3" {
+		t.Errorf("Unexpected queries: %s / %s", q1, q2)
+	}
+}
@@ -1,6 +1,9 @@
 package solr
 
-import "encoding/json"
+import (
+	"bytes"
+	"encoding/json"
+)
 
 // The *Raw structs are used to unmarshall the JSON from Solr
 // via Go's built-in functions. They are not exposed outside
@@ -28,6 +31,31 @@ type highlightRow map[string][]string
 type errorRaw struct {
 	Trace string `json:"trace"`
 	Code  int    `json:"code"`
+	Msg   string `json:"msg"`
+	// Solr returns the metadata as an array in the form
+	// [key1, value1, key2, value2] (or as a map if json.nl=map)
+	Metadata interface{} `json:"metadata"`
+}
+
+func (e errorRaw) isEmpty() bool {
+	return e.Trace == "" && e.Code == 0 && e.Msg == ""
+}
+
+// metadata returns the value for the given key in the error metadata.
+func (e errorRaw) metadata(key string) string {
+	switch metadata := e.Metadata.(type) {
+	case []interface{}:
+		for i := 0; i+1 < len(metadata); i += 2 {
+			if k, ok := metadata[i].(string); ok && k == key {
+				value, _ := metadata[i+1].(string)
+				return value
+			}
+		}
+	case map[string]interface{}:
+		value, _ := metadata[key].(string)
+		return value
+	}
+	return ""
 }
 
 type responseRaw struct {
@@ -36,12 +64,19 @@ type responseRaw struct {
 	Error        errorRaw                `json:"error"`
 	FacetCounts  facetCountsRaw          `json:"facet_counts"`
 	Highlighting map[string]highlightRow `json:"highlighting"`
+	NextCursor   string                  `json:"nextCursorMark"`
+	JSONFacets   map[string]interface{}  `json:"facets"`
 	Raw          string                  `json:"raw"`
 }
 
+// NewResponseRaw parses the JSON returned by Solr. Numbers are decoded
+// as json.Number (rather than float64) so that large values like
+// _version_ do not lose precision.
 func NewResponseRaw(rawBytes []byte) (responseRaw, error) {
 	var response responseRaw
-	err := json.Unmarshal([]byte(rawBytes), &response)
+	decoder := json.NewDecoder(bytes.NewReader(rawBytes))
+	decoder.UseNumber()
+	err := decoder.Decode(&response)
 	if err != nil {
 		return response, err
 	}
@@ -50,6 +85,30 @@ func NewResponseRaw(rawBytes []byte) (responseRaw, error) {
 }
 
 type facetCountsRaw struct {
-	Queries interface{}              `json:"facet_queries"`
-	Fields  map[string][]interface{} `json:"facet_fields"`
+	Queries   map[string]interface{}            `json:"facet_queries"`
+	Fields    map[string][]interface{}          `json:"facet_fields"`
+	Ranges    map[string]facetRangeRaw          `json:"facet_ranges"`
+	Intervals map[string]map[string]interface{} `json:"facet_intervals"`
+	Pivots    map[string][]pivotRaw             `json:"facet_pivot"`
+}
+
+// A node in a pivot facet, e.g. {"field":"format","value":"Book",
+// "count":10,"pivot":[...]}
+type pivotRaw struct {
+	Field string      `json:"field"`
+	Value interface{} `json:"value"`
+	Count interface{} `json:"count"`
+	Pivot []pivotRaw  `json:"pivot"`
+}
+
+// counts is an array in the form [value1, count1, value2, count2]
+// (start, end, and gap are numbers or dates depending on the field)
+type facetRangeRaw struct {
+	Counts  []interface{} `json:"counts"`
+	Start   interface{}   `json:"start"`
+	End     interface{}   `json:"end"`
+	Gap     interface{}   `json:"gap"`
+	Before  interface{}   `json:"before"`
+	After   interface{}   `json:"after"`
+	Between interface{}   `json:"between"`
 }
@@ -0,0 +1,78 @@
+package solr
+
+import (
+	"context"
+	"strings"
+)
+
+// RealTimeGetResult represents the documents fetched via RealTimeGet().
+type RealTimeGetResult struct {
+	Documents []Document // Documents found (in the order they were requested)
+	Missing   []string   // IDs that were not found
+}
+
+// ForId returns the document with the id indicated.
+func (r RealTimeGetResult) ForId(id string) (Document, bool) {
+	for _, doc := range r.Documents {
+		if doc.Id() == id {
+			return doc, true
+		}
+	}
+	return Document{}, false
+}
+
+// RealTimeGet fetches documents by id via Solr's /get handler. Unlike
+// Get() it returns the latest version of the documents even if they
+// have not been committed yet and the ids do not need to be escaped.
+//
+// fl indicates the fields to fetch (empty for all fields).
+func (s Solr) RealTimeGet(fl []string, ids ...string) (RealTimeGetResult, error) {
+	return s.RealTimeGetContext(context.Background(), fl, ids...)
+}
+
+// RealTimeGetContext is like RealTimeGet but uses the provided context
+// for the HTTP request to Solr.
+func (s Solr) RealTimeGetContext(ctx context.Context, fl []string, ids ...string) (RealTimeGetResult, error) {
+	if len(ids) == 0 {
+		return RealTimeGetResult{}, nil
+	}
+
+	qs := qsAdd("wt", "json")
+	for _, id := range ids {
+		qs += qsAdd("ids", escapeRealTimeGetId(id))
+	}
+	if len(fl) > 0 {
+		// make sure the id is always returned so that we can
+		// tell which documents are missing.
+		qs += qsAddMany("fl", append([]string{"id"}, fl...))
+	}
+
+	url := s.CoreUrl + "/get?" + qs
+	raw, err := s.httpGet(ctx, url)
+	if err != nil {
+		return RealTimeGetResult{}, err
+	}
+
+	found := map[string]Document{}
+	for _, rawDoc := range raw.Data.Documents {
+		doc := newDocumentFromSolrDoc(rawDoc)
+		found[doc.Id()] = doc
+	}
+
+	result := RealTimeGetResult{}
+	for _, id := range ids {
+		if doc, ok := found[id]; ok {
+			result.Documents = append(result.Documents, doc)
+		} else {
+			result.Missing = append(result.Missing, id)
+		}
+	}
+	return result, nil
+}
+
+// Solr splits the ids parameter on commas, commas (and backslashes)
+// inside an id must be escaped with a backslash.
+func escapeRealTimeGetId(id string) string {
+	id = strings.Replace(id, `\`, `\\`, -1)
+	return strings.Replace(id, ",", `\,`, -1)
+}
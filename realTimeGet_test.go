@@ -0,0 +1,36 @@
+package solr
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestRealTimeGet(t *testing.T) {
+	var ids []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ids = r.URL.Query()["ids"]
+		w.Write([]byte(`{"response":{"numFound":1,"start":0,"docs":[{"id":"a,b","title":"one"}]}}`))
+	}))
+	defer server.Close()
+
+	solr := New(server.URL, false)
+	result, err := solr.RealTimeGet(nil, "a,b", "c")
+	if err != nil {
+		t.Fatalf("RealTimeGet error: %s", err)
+	}
+
+	if !reflect.DeepEqual(ids, []string{`a\,b`, "c"}) {
+		t.Errorf("Unexpected ids: %v", ids)
+	}
+
+	doc, found := result.ForId("a,b")
+	if !found || doc.Value("title") != "one" {
+		t.Errorf("Unexpected documents: %v", result.Documents)
+	}
+
+	if !reflect.DeepEqual(result.Missing, []string{"c"}) {
+		t.Errorf("Unexpected missing ids: %v", result.Missing)
+	}
+}
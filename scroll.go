@@ -0,0 +1,94 @@
+package solr
+
+import (
+	"context"
+	"io"
+)
+
+// Scroller iterates over all the documents that match a search using
+// Solr's cursorMark (deep paging). Unlike paging with Start it does
+// not get slower as it goes deeper in the results. See Solr.Scroll()
+type Scroller struct {
+	UniqueKey string // Unique key field of the core (default "id")
+
+	solr   Solr
+	params SearchParams
+	cursor string
+	docs   []Document
+	done   bool
+}
+
+// Scroll returns a Scroller to iterate over all the documents that
+// match the search parameters, for example:
+//
+// 	scroller := s.Scroll(params)
+// 	for {
+// 		doc, err := scroller.Next()
+// 		if err == io.EOF {
+// 			break
+// 		}
+// 		...
+// 	}
+//
+// The unique key of the core is automatically added to the sort (as
+// required by Solr for cursors) and params.Start is ignored.
+// params.Rows indicates how many documents are fetched on each request.
+func (s Solr) Scroll(params SearchParams) *Scroller {
+	return &Scroller{UniqueKey: "id", solr: s, params: params, cursor: "*"}
+}
+
+// Next returns the next document or io.EOF when there are no more
+// documents.
+func (sc *Scroller) Next() (Document, error) {
+	return sc.NextContext(context.Background())
+}
+
+// NextContext is like Next but uses the provided context for the HTTP
+// request to Solr.
+func (sc *Scroller) NextContext(ctx context.Context) (Document, error) {
+	for len(sc.docs) == 0 {
+		if sc.done {
+			return Document{}, io.EOF
+		}
+		if err := sc.fetch(ctx); err != nil {
+			return Document{}, err
+		}
+	}
+
+	doc := sc.docs[0]
+	sc.docs = sc.docs[1:]
+	return doc, nil
+}
+
+// fetch gets the next batch of documents from Solr.
+func (sc *Scroller) fetch(ctx context.Context) error {
+	params := sc.params
+	params.Start = 0
+	params.Sort = sc.sort()
+
+	url := sc.solr.CoreUrl + "/select?" + params.toSolrQueryString() + qsAdd("cursorMark", sc.cursor)
+	raw, err := sc.solr.httpGet(ctx, url)
+	if err != nil {
+		return err
+	}
+
+	sc.docs = newDocumentFromSolrResponse(raw)
+	if raw.NextCursor == "" || raw.NextCursor == sc.cursor {
+		// Solr returns the same cursor when there are no more results
+		sc.done = true
+	}
+	sc.cursor = raw.NextCursor
+	return nil
+}
+
+// sort returns the sort of the params with the unique key as
+// the tiebreaker.
+func (sc *Scroller) sort() []SortClause {
+	for _, clause := range sc.params.Sort {
+		if clause.Field == sc.UniqueKey {
+			return sc.params.Sort
+		}
+	}
+	sort := append([]SortClause{}, sc.params.Sort...)
+	return append(sort, Asc(sc.UniqueKey))
+}
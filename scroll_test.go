@@ -0,0 +1,48 @@
+package solr
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestScroll(t *testing.T) {
+	pages := map[string]string{
+		"*":  `{"response":{"docs":[{"id":"1"},{"id":"2"}]},"nextCursorMark":"c1"}`,
+		"c1": `{"response":{"docs":[{"id":"3"}]},"nextCursorMark":"c2"}`,
+		"c2": `{"response":{"docs":[]},"nextCursorMark":"c2"}`,
+	}
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		qs := r.URL.Query()
+		if qs.Get("sort") != "year_i desc,id asc" || qs.Get("start") != "" {
+			t.Errorf("Unexpected request: %s", r.URL.RawQuery)
+		}
+		fmt.Fprint(w, pages[qs.Get("cursorMark")])
+	}))
+	defer server.Close()
+
+	params := NewSearchParams("*", map[string]string{}, map[string]string{})
+	params.Sort = []SortClause{Desc("year_i")}
+	params.Start = 20
+	scroller := New(server.URL, false).Scroll(params)
+
+	ids := ""
+	for {
+		doc, err := scroller.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Next error: %s", err)
+		}
+		ids += doc.Id()
+	}
+
+	if ids != "123" || requests != 3 {
+		t.Errorf("Unexpected results: %s (%d requests)", ids, requests)
+	}
+}
@@ -2,6 +2,8 @@ package solr
 
 import (
 	"net/url"
+
+	"github.com/hectorcorrea/solr/query"
 )
 
 const defaultRows = 10
@@ -10,17 +12,22 @@ const defaultRows = 10
 // search in Solr.
 type SearchParams struct {
 	Q             string
+	Query         query.Query // When not nil it is used instead of Q (see package query)
 	Fl            []string
 	Rows          int
 	Start         int
+	Sort          []SortClause      // Values that will be passed as the sort parameter.
+	SortOptions   []SortOption      // Sort options to offer to the user (see SearchResponse.SortOptions)
 	FilterQueries filterQueries     // Values that will be passed as the fq parameter.
 	Facets        Facets            // Facets to request from Solr.
+	PivotFacets   PivotFacets       // Pivot facets to request from Solr.
+	JSONFacets    JSONFacets        // Facets to request via the JSON Facet API.
 	Options       map[string]string // Options to pass straight to Solr (e.g. defType: "edismax")
 }
 
 // NewSearchParamsFromQs creates a SearchParams object from a query string.
 // This method will automatically pickup several known parameters from the
-// query string (q, rows, start, and fq).
+// query string (q, rows, start, sort, and fq).
 //
 // qs typically an instance of req.URL.Query() from a web handler.
 func NewSearchParamsFromQs(qs url.Values, options map[string]string,
@@ -30,6 +37,7 @@ func NewSearchParamsFromQs(qs url.Values, options map[string]string,
 		Q:             qsGet(qs, "q", "*"),
 		Rows:          qsGetInt(qs, "rows", defaultRows),
 		Start:         qsGetInt(qs, "start", 0),
+		Sort:          ParseSort(qsGet(qs, "sort", "")),
 		FilterQueries: newFilterQueries(qs["fq"]),
 		Options:       options,
 		Facets:        NewFacetsFromDefinitions(facets),
@@ -52,10 +60,16 @@ func NewSearchParams(q string, options map[string]string,
 
 func (params SearchParams) toSolrQueryString() string {
 	qs := ""
-	qs += qsAddDefault("q", params.Q, "*")
+	qs += qsAddDefault("q", params.q(), "*")
 	qs += qsAddMany("fl", params.Fl)
-	qs += params.FilterQueries.toQueryString()
+	qs += qsAdd("sort", sortToString(params.Sort))
+	qs += params.FilterQueries.toQueryString(params.Facets)
 	qs += params.Facets.toQueryString()
+	if len(params.Facets) == 0 && len(params.PivotFacets) > 0 {
+		qs += qsAdd("facet", "on")
+	}
+	qs += params.PivotFacets.toQueryString()
+	qs += params.JSONFacets.toQueryString()
 
 	if params.Start > 0 {
 		qs += qsAddInt("start", params.Start)
@@ -70,3 +84,11 @@ func (params SearchParams) toSolrQueryString() string {
 	}
 	return qs
 }
+
+// q returns the query to send to Solr.
+func (params SearchParams) q() string {
+	if params.Query != nil {
+		return params.Query.String()
+	}
+	return params.Q
+}
@@ -14,19 +14,22 @@ type SearchResponse struct {
 	NumFound    int
 	Start       int
 	Rows        int
-	Documents   []Document // Documents returned by Solr (including highlight information)
-	Facets      Facets     // Facet information (field, title, and values)
-	Url         string     // URL to execute this search
-	UrlNoQ      string     // URL to execute this search without the Q parameter
-	NextPageUrl string     // URL to get the next batch of results
-	PrevPageUrl string     // URL to get the previous batch of results
+	Documents   []Document      // Documents returned by Solr (including highlight information)
+	Facets      Facets          // Facet information (field, title, and values)
+	PivotFacets PivotFacets     // Pivot facet information (fields, title, and nodes)
+	SortOptions []SortOption    // Sort options (from the params) with the URL to sort by each of them
+	JSONFacets  JSONFacetResult // Results of the JSON Facet API (when requested via JSONFacets)
+	Url         string          // URL to execute this search
+	UrlNoQ      string          // URL to execute this search without the Q parameter
+	NextPageUrl string          // URL to get the next batch of results
+	PrevPageUrl string          // URL to get the previous batch of results
 	Raw         string
 }
 
 func newSearchResponse(params SearchParams, raw responseRaw) SearchResponse {
 	r := SearchResponse{
 		Params:    params,
-		Q:         params.Q,
+		Q:         params.q(),
 		NumFound:  raw.Data.NumFound,
 		Start:     raw.Data.Start,
 		Rows:      params.Rows,
@@ -46,28 +49,41 @@ func newSearchResponse(params SearchParams, raw responseRaw) SearchResponse {
 	}
 
 	r.Facets = orderedFacets
+	r.PivotFacets = newPivotFacets(params.PivotFacets, raw.FacetCounts.Pivots, params.FilterQueries)
+	if raw.JSONFacets != nil {
+		r.JSONFacets = newJSONFacetResult(raw.JSONFacets)
+	}
 	r.Url = r.toQueryString(r.Q, r.Start)
 	r.UrlNoQ = r.toQueryString("", r.Start)
 	r.NextPageUrl = r.toQueryString(r.Q, r.Start+r.Rows)
 	r.PrevPageUrl = r.toQueryString(r.Q, r.Start-r.Rows)
 
+	for _, option := range params.SortOptions {
+		// Changing the sort takes the user back to the first page.
+		option.Url = r.toQueryStringSort(r.Q, 0, option.Sort)
+		option.Active = sortEqual(option.Sort, params.Sort)
+		r.SortOptions = append(r.SortOptions, option)
+	}
+
 	return r
 }
 
 func (r SearchResponse) toQueryString(q string, start int) string {
+	return r.toQueryStringSort(q, start, r.Params.Sort)
+}
+
+func (r SearchResponse) toQueryStringSort(q string, start int, sort []SortClause) string {
 	qs := ""
 
 	if q != "" {
-		qs += qsAddRaw("q", q)
+		qs += qsAdd("q", q)
 	}
 
-	for _, facet := range r.Facets {
-		for _, value := range facet.Values {
-			if value.Active {
-				valueEnc := url.QueryEscape(value.Text)
-				qs += qsAddRaw("fq", facet.Field+"|"+valueEnc)
-			}
-		}
+	qs += qsAdd("sort", sortToString(sort))
+
+	for _, fq := range r.activeFilters() {
+		valueEnc := url.QueryEscape(fq.Value)
+		qs += qsAddRaw("fq", fq.Field+"|"+valueEnc)
 	}
 
 	if start > 0 {
@@ -80,6 +96,28 @@ func (r SearchResponse) toQueryString(q string, start int) string {
 	return qs
 }
 
+// activeFilters returns the filters of the active facet values and
+// pivot nodes (without duplicates).
+func (r SearchResponse) activeFilters() filterQueries {
+	fqs := filterQueries{}
+	add := func(fq filterQuery) {
+		if !fqs.HasFieldValue(fq.Field, fq.Value) {
+			fqs = append(fqs, fq)
+		}
+	}
+	for _, facet := range r.Facets {
+		for _, value := range facet.Values {
+			if value.Active {
+				add(filterQuery{Field: facet.Field, Value: value.filter()})
+			}
+		}
+	}
+	for _, fq := range r.PivotFacets.activeFilters() {
+		add(fq)
+	}
+	return fqs
+}
+
 // Creates a new Facets object from the raw FacetCounts from Solr.
 // 	`counts` contains the facet data as reported by Solr.
 func (r SearchResponse) facetsFromResponse(counts facetCountsRaw) Facets {
@@ -94,7 +132,8 @@ func (r SearchResponse) facetsFromResponse(counts facetCountsRaw) Facets {
 			// value and count properties.
 			for i := 0; i < len(tokens); i += 2 {
 				text := tokens[i].(string)
-				count := int(tokens[i+1].(float64))
+				count64, _ := convertInt64(tokens[i+1])
+				count := int(count64)
 				// Mark the facet for this value as active if it is
 				// present on the FilterQueries
 				active := r.Params.FilterQueries.HasFieldValue(fieldName, text)
@@ -111,6 +150,28 @@ func (r SearchResponse) facetsFromResponse(counts facetCountsRaw) Facets {
 
 		facets = append(facets, facet)
 	}
+
+	for fieldName, rangeRaw := range counts.Ranges {
+		facet := r.newFacet(fieldName)
+		facet.addRangeValues(rangeRaw, r.Params.FilterQueries)
+		facets = append(facets, facet)
+	}
+
+	for fieldName, intervals := range counts.Intervals {
+		facet := r.newFacet(fieldName)
+		facet.addIntervalValues(intervals, r.Params.FilterQueries)
+		facets = append(facets, facet)
+	}
+
+	if counts.Queries != nil {
+		for _, def := range r.Params.Facets {
+			if len(def.Queries) > 0 {
+				facet := r.newFacet(def.Field)
+				facet.addQueryValues(counts.Queries, r.Params.FilterQueries)
+				facets = append(facets, facet)
+			}
+		}
+	}
 	return facets
 }
 
@@ -119,8 +180,11 @@ func (r SearchResponse) newFacet(field string) FacetField {
 	for _, def := range r.Params.Facets {
 		if def.Field == facet.Field {
 			// if the field is on the facets indicated on the params
-			// (it should always be) grab the Title from it
+			// (it should always be) grab the Title and definition from it
 			facet.Title = def.Title
+			facet.Range = def.Range
+			facet.Intervals = def.Intervals
+			facet.Queries = def.Queries
 			break
 		}
 	}
@@ -44,38 +44,70 @@ package solr
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"log"
 	"net/http"
+	"strings"
 )
 
+// errRequestDone is reported to the writer in PostEncoded() when the
+// request to Solr finished before the whole body was written.
+var errRequestDone = errors.New("Solr request finished")
+
 // The main class to drive interaction with Solr.
 type Solr struct {
-	CoreUrl string
-	Verbose bool
+	CoreUrl     string
+	Verbose     bool
+	httpClient  *http.Client // nil means http.DefaultClient
+	userAgent   string
+	credentials Credentials
+	updateOpts  *UpdateOptions // nil means DefaultUpdateOptions()
 }
 
 // Creates a new instance of Solr.
 // When verbose = true it will log.Printf() the HTTP requests to Solr.
-func New(coreUrl string, verbose bool) Solr {
-	return Solr{CoreUrl: coreUrl, Verbose: verbose}
+//
+// Options can be used to customize the HTTP client used to talk to
+// Solr, for example:
+//
+// 	s := solr.New(coreUrl, false, solr.WithTimeout(5*time.Second))
+func New(coreUrl string, verbose bool, options ...Option) Solr {
+	s := Solr{CoreUrl: coreUrl, Verbose: verbose}
+	for _, option := range options {
+		option(&s)
+	}
+	return s
 }
 
 func (s Solr) Count() (int, error) {
+	return s.CountContext(context.Background())
+}
+
+// CountContext is like Count but uses the provided context for
+// the HTTP request to Solr.
+func (s Solr) CountContext(ctx context.Context) (int, error) {
 	options := map[string]string{"rows": "0", "defType": "edismax", "wt": "json"}
 	facets := map[string]string{}
 	params := NewSearchParams("*", options, facets)
-	r, err := s.Search(params)
+	r, err := s.SearchContext(ctx, params)
 	return r.NumFound, err
 }
 
 // Get fetches a single document from Solr.
 func (s Solr) Get(params GetParams) (Document, error) {
+	return s.GetContext(context.Background(), params)
+}
+
+// GetContext is like Get but uses the provided context for
+// the HTTP request to Solr.
+func (s Solr) GetContext(ctx context.Context, params GetParams) (Document, error) {
 	url := s.CoreUrl + "/select?" + params.toSolrQueryString()
-	raw, err := s.httpGet(url)
+	raw, err := s.httpGet(ctx, url)
 	if err != nil {
 		return Document{}, err
 	}
@@ -84,16 +116,21 @@ func (s Solr) Get(params GetParams) (Document, error) {
 	if count == 0 {
 		return Document{}, nil
 	} else if count > 1 {
-		msg := fmt.Sprintf("More than one document was found (Q=%s)", params.Q)
-		return Document{}, errors.New(msg)
+		return Document{}, fmt.Errorf("%w (Q=%s)", ErrMultipleDocuments, params.Q)
 	}
 	return newDocumentFromSolrDoc(raw.Data.Documents[0]), err
 }
 
 // Issues a search with the values indicated in the paramers.
 func (s Solr) Search(params SearchParams) (SearchResponse, error) {
+	return s.SearchContext(context.Background(), params)
+}
+
+// SearchContext is like Search but uses the provided context for
+// the HTTP request to Solr.
+func (s Solr) SearchContext(ctx context.Context, params SearchParams) (SearchResponse, error) {
 	url := s.CoreUrl + "/select?" + params.toSolrQueryString()
-	raw, err := s.httpGet(url)
+	raw, err := s.httpGet(ctx, url)
 	if err != nil {
 		return SearchResponse{}, err
 	}
@@ -103,27 +140,62 @@ func (s Solr) Search(params SearchParams) (SearchResponse, error) {
 // Updates a single document in Solr with the data in the
 // document provided.
 func (s Solr) PostDoc(doc Document) error {
+	return s.PostDocContext(context.Background(), doc)
+}
+
+// PostDocContext is like PostDoc but uses the provided context for
+// the HTTP request to Solr.
+func (s Solr) PostDocContext(ctx context.Context, doc Document) error {
 	docs := []Document{doc}
-	return s.PostDocs(docs)
+	return s.PostDocsContext(ctx, docs)
 }
 
 // Updates an array of documents in Solr.
 func (s Solr) PostDocs(docs []Document) error {
-	// Extract the data from the documents
-	// (i.e. only the data, without the highlight properties)
-	data := []map[string]interface{}{}
-	for _, doc := range docs {
-		data = append(data, doc.Data)
+	return s.PostDocsContext(context.Background(), docs)
+}
+
+// PostDocsContext is like PostDocs but uses the provided context for
+// the HTTP request to Solr.
+func (s Solr) PostDocsContext(ctx context.Context, docs []Document) error {
+	return s.PostDocsWithOptionsContext(ctx, docs, s.updateOptions())
+}
+
+// UpdateIfVersion updates a single document in Solr only if its
+// _version_ in the index matches the version provided (typically
+// the value of doc.Version() when the document was fetched.)
+//
+// As in Solr, a version greater than 1 must match exactly, a version
+// of 1 requires the document to exist, and a negative version requires
+// the document to not exist. When the version does not match the error
+// returned satisfies errors.Is(err, ErrVersionConflict).
+func (s Solr) UpdateIfVersion(doc Document, version int64) error {
+	return s.UpdateIfVersionContext(context.Background(), doc, version)
+}
+
+// UpdateIfVersionContext is like UpdateIfVersion but uses the provided
+// context for the HTTP request to Solr.
+func (s Solr) UpdateIfVersionContext(ctx context.Context, doc Document, version int64) error {
+	datum := map[string]interface{}{}
+	for field, value := range doc.solrData() {
+		datum[field] = value
 	}
-	return s.Post(data)
+	datum[versionField] = version
+	return s.PostOneContext(ctx, datum)
 }
 
 // Updates a single document in Solr. Uses plain Go map[string]interface{}
 // object rather than a Document object. The map key is represents
 // the field name and the map value the field value.
 func (s Solr) PostOne(datum map[string]interface{}) error {
+	return s.PostOneContext(context.Background(), datum)
+}
+
+// PostOneContext is like PostOne but uses the provided context for
+// the HTTP request to Solr.
+func (s Solr) PostOneContext(ctx context.Context, datum map[string]interface{}) error {
 	data := []map[string]interface{}{datum}
-	return s.Post(data)
+	return s.PostContext(ctx, data)
 }
 
 // Post issues an HTTP POST to Solr with data data of the
@@ -133,97 +205,365 @@ func (s Solr) PostOne(datum map[string]interface{}) error {
 // rather than an array of Document objects. The map key represents
 // the field name and the map value the field value.
 func (s Solr) Post(data []map[string]interface{}) error {
-	bytes, err := json.Marshal(data)
-	if err != nil {
-		return err
-	}
-	return s.PostString(string(bytes))
+	return s.PostContext(context.Background(), data)
+}
+
+// PostContext is like Post but uses the provided context for
+// the HTTP request to Solr.
+func (s Solr) PostContext(ctx context.Context, data []map[string]interface{}) error {
+	return s.PostWithOptionsContext(ctx, data, s.updateOptions())
 }
 
 // PostString issues an HTTP POST the `/update` handler to update or
 // add one or more documents to Solr. The string is assummed to be
 // a valid representation of one or more documents.
 func (s Solr) PostString(data string) error {
-	contentType := "application/json"
-	params := "wt=json&commit=true"
-	url := s.CoreUrl + "/update?" + params
-	r, err := s.httpPost(url, contentType, data)
-	if err != nil {
-		return err
+	return s.PostStringContext(context.Background(), data)
+}
+
+// PostStringContext is like PostString but uses the provided context for
+// the HTTP request to Solr.
+func (s Solr) PostStringContext(ctx context.Context, data string) error {
+	return s.PostStringWithOptionsContext(ctx, data, s.updateOptions())
+}
+
+// PostDocsWithOptions is like PostDocs but uses the provided update
+// options (e.g. to use commitWithin rather than a hard commit on
+// every call.)
+func (s Solr) PostDocsWithOptions(docs []Document, opts UpdateOptions) error {
+	return s.PostDocsWithOptionsContext(context.Background(), docs, opts)
+}
+
+// PostDocsWithOptionsContext is like PostDocsWithOptions but uses the
+// provided context for the HTTP request to Solr.
+func (s Solr) PostDocsWithOptionsContext(ctx context.Context, docs []Document, opts UpdateOptions) error {
+	// Extract the data from the documents
+	// (i.e. the data and child documents, without the highlight properties)
+	data := []map[string]interface{}{}
+	for _, doc := range docs {
+		data = append(data, doc.solrData())
 	}
+	return s.PostWithOptionsContext(ctx, data, opts)
+}
+
+// PostWithOptions is like Post but uses the provided update options.
+func (s Solr) PostWithOptions(data []map[string]interface{}, opts UpdateOptions) error {
+	return s.PostWithOptionsContext(context.Background(), data, opts)
+}
 
-	var response responseRaw
-	err = json.Unmarshal([]byte(r), &response)
+// PostWithOptionsContext is like PostWithOptions but uses the
+// provided context for the HTTP request to Solr.
+func (s Solr) PostWithOptionsContext(ctx context.Context, data []map[string]interface{}, opts UpdateOptions) error {
+	body, err := json.Marshal(data)
 	if err != nil {
 		return err
 	}
+	return s.PostStreamWithOptionsContext(ctx, bytes.NewReader(body), "application/json", opts)
+}
 
-	if response.Header.Status != 0 {
-		errorMsg := fmt.Sprintf("Solr returned status %d", response.Header.Status)
-		return errors.New(errorMsg)
-	}
+// PostStringWithOptions is like PostString but uses the provided
+// update options.
+func (s Solr) PostStringWithOptions(data string, opts UpdateOptions) error {
+	return s.PostStringWithOptionsContext(context.Background(), data, opts)
+}
+
+// PostStringWithOptionsContext is like PostStringWithOptions but uses
+// the provided context for the HTTP request to Solr.
+func (s Solr) PostStringWithOptionsContext(ctx context.Context, data string, opts UpdateOptions) error {
+	return s.PostStreamWithOptionsContext(ctx, strings.NewReader(data), "application/json", opts)
+}
+
+// PostStream issues an HTTP POST to the `/update` handler with the
+// body read from the reader provided. contentType indicates the format
+// of the body (e.g. "application/json", "text/xml", or "text/csv").
+//
+// The body is streamed to Solr rather than loaded in memory.
+func (s Solr) PostStream(body io.Reader, contentType string) error {
+	return s.PostStreamContext(context.Background(), body, contentType)
+}
 
-	return nil
+// PostStreamContext is like PostStream but uses the provided context
+// for the HTTP request to Solr.
+func (s Solr) PostStreamContext(ctx context.Context, body io.Reader, contentType string) error {
+	return s.PostStreamWithOptionsContext(ctx, body, contentType, s.updateOptions())
+}
+
+// PostStreamWithOptions is like PostStream but uses the provided
+// update options.
+func (s Solr) PostStreamWithOptions(body io.Reader, contentType string, opts UpdateOptions) error {
+	return s.PostStreamWithOptionsContext(context.Background(), body, contentType, opts)
+}
+
+// PostStreamWithOptionsContext is like PostStreamWithOptions but uses
+// the provided context for the HTTP request to Solr.
+func (s Solr) PostStreamWithOptionsContext(ctx context.Context, body io.Reader, contentType string, opts UpdateOptions) error {
+	url := s.CoreUrl + "/update?" + opts.toQueryString()
+	_, err := s.httpPost(ctx, url, contentType, body)
+	return err
+}
+
+// PostEncoded streams documents to Solr's `/update` handler. The
+// function provided is called to write the documents via the
+// DocumentEncoder, each document is encoded straight into the body of
+// the HTTP request so that memory usage stays constant regardless of
+// the number of documents.
+//
+// If the function returns an error the request to Solr is aborted
+// and the error is returned.
+func (s Solr) PostEncoded(opts UpdateOptions, write func(enc *DocumentEncoder) error) error {
+	return s.PostEncodedContext(context.Background(), opts, write)
+}
+
+// PostEncodedContext is like PostEncoded but uses the provided
+// context for the HTTP request to Solr.
+func (s Solr) PostEncodedContext(ctx context.Context, opts UpdateOptions, write func(enc *DocumentEncoder) error) error {
+	pr, pw := io.Pipe()
+	writeErr := make(chan error, 1)
+	go func() {
+		enc := newDocumentEncoder(pw)
+		err := write(enc)
+		if err == nil {
+			err = enc.close()
+		}
+		// Closing with a nil error is the same as Close()
+		pw.CloseWithError(err)
+		writeErr <- err
+	}()
+
+	err := s.PostStreamWithOptionsContext(ctx, pr, "application/json", opts)
+	// Make sure the writer is not blocked if the request
+	// finished before the whole body was read.
+	pr.CloseWithError(errRequestDone)
+	if wErr := <-writeErr; wErr != nil && !errors.Is(wErr, errRequestDone) {
+		return wErr
+	}
+	return err
 }
 
 // Deletes from Solr all the documents
 func (s Solr) DeleteAll() error {
-	payload := "<delete><query>*:*</query></delete>"
-	return s.deletePayload(payload)
+	return s.DeleteAllContext(context.Background())
+}
+
+// DeleteAllContext is like DeleteAll but uses the provided context for
+// the HTTP request to Solr.
+func (s Solr) DeleteAllContext(ctx context.Context) error {
+	return s.DeleteAllWithOptionsContext(ctx, s.updateOptions())
+}
+
+// DeleteAllWithOptions is like DeleteAll but uses the provided
+// update options.
+func (s Solr) DeleteAllWithOptions(opts UpdateOptions) error {
+	return s.DeleteAllWithOptionsContext(context.Background(), opts)
+}
+
+// DeleteAllWithOptionsContext is like DeleteAllWithOptions but uses
+// the provided context for the HTTP request to Solr.
+func (s Solr) DeleteAllWithOptionsContext(ctx context.Context, opts UpdateOptions) error {
+	return s.DeleteByQueryWithOptionsContext(ctx, "*:*", opts)
+}
+
+// DeleteByQuery deletes from Solr all the documents that match
+// the query.
+func (s Solr) DeleteByQuery(query string) error {
+	return s.DeleteByQueryContext(context.Background(), query)
+}
+
+// DeleteByQueryContext is like DeleteByQuery but uses the provided
+// context for the HTTP request to Solr.
+func (s Solr) DeleteByQueryContext(ctx context.Context, query string) error {
+	return s.DeleteByQueryWithOptionsContext(ctx, query, s.updateOptions())
+}
+
+// DeleteByQueryWithOptions is like DeleteByQuery but uses the
+// provided update options.
+func (s Solr) DeleteByQueryWithOptions(query string, opts UpdateOptions) error {
+	return s.DeleteByQueryWithOptionsContext(context.Background(), query, opts)
+}
+
+// DeleteByQueryWithOptionsContext is like DeleteByQueryWithOptions
+// but uses the provided context for the HTTP request to Solr.
+func (s Solr) DeleteByQueryWithOptionsContext(ctx context.Context, query string, opts UpdateOptions) error {
+	if query == "" {
+		return ErrEmptyQuery
+	}
+	payload := map[string]interface{}{
+		"delete": map[string]string{"query": query},
+	}
+	return s.deletePayload(ctx, payload, opts)
 }
 
 // Deletes from Solr the documents with the IDs indicated.
 func (s Solr) Delete(ids []string) error {
-	payload := "<delete>\r\n"
-	for _, id := range ids {
-		payload += "\t<id>" + id + "</id>\r\n"
+	return s.DeleteContext(context.Background(), ids)
+}
+
+// DeleteContext is like Delete but uses the provided context for
+// the HTTP request to Solr.
+func (s Solr) DeleteContext(ctx context.Context, ids []string) error {
+	return s.DeleteWithOptionsContext(ctx, ids, s.updateOptions())
+}
+
+// DeleteWithOptions is like Delete but uses the provided update
+// options.
+func (s Solr) DeleteWithOptions(ids []string, opts UpdateOptions) error {
+	return s.DeleteWithOptionsContext(context.Background(), ids, opts)
+}
+
+// DeleteWithOptionsContext is like DeleteWithOptions but uses the
+// provided context for the HTTP request to Solr.
+func (s Solr) DeleteWithOptionsContext(ctx context.Context, ids []string, opts UpdateOptions) error {
+	if len(ids) == 0 {
+		return nil
 	}
-	payload += "</delete>"
-	return s.deletePayload(payload)
+	payload := map[string]interface{}{"delete": ids}
+	return s.deletePayload(ctx, payload, opts)
 }
 
-func (s Solr) deletePayload(payload string) error {
-	// notice that the request body (contentType) is in XML
-	// but the response (wt) is in JSON
-	contentType := "text/xml"
-	params := "wt=json&commit=true"
-	url := s.CoreUrl + "/update?" + params
+// DeleteId identifies a document to delete with DeleteDocs().
+type DeleteId struct {
+	Id      string `json:"id"`
+	Version int64  `json:"_version_,omitempty"` // When not zero Solr enforces it (see UpdateIfVersion)
+	Route   string `json:"_route_,omitempty"`   // Shard to route the delete to (SolrCloud)
+}
 
-	r, err := s.httpPost(url, contentType, payload)
-	if err != nil {
-		return err
+// DeleteDocs deletes from Solr the documents indicated. Unlike
+// Delete() it allows indicating the version and route of each
+// document.
+func (s Solr) DeleteDocs(deletes []DeleteId) error {
+	return s.DeleteDocsContext(context.Background(), deletes)
+}
+
+// DeleteDocsContext is like DeleteDocs but uses the provided context
+// for the HTTP request to Solr.
+func (s Solr) DeleteDocsContext(ctx context.Context, deletes []DeleteId) error {
+	return s.DeleteDocsWithOptionsContext(ctx, deletes, s.updateOptions())
+}
+
+// DeleteDocsWithOptions is like DeleteDocs but uses the provided
+// update options.
+func (s Solr) DeleteDocsWithOptions(deletes []DeleteId, opts UpdateOptions) error {
+	return s.DeleteDocsWithOptionsContext(context.Background(), deletes, opts)
+}
+
+// DeleteDocsWithOptionsContext is like DeleteDocsWithOptions but uses
+// the provided context for the HTTP request to Solr.
+func (s Solr) DeleteDocsWithOptionsContext(ctx context.Context, deletes []DeleteId, opts UpdateOptions) error {
+	if len(deletes) == 0 {
+		return nil
 	}
+	payload := map[string]interface{}{"delete": deletes}
+	return s.deletePayload(ctx, payload, opts)
+}
 
-	var response responseRaw
-	err = json.Unmarshal([]byte(r), &response)
+// deletePayload sends a JSON delete command to Solr.
+func (s Solr) deletePayload(ctx context.Context, payload interface{}, opts UpdateOptions) error {
+	body, err := json.Marshal(payload)
 	if err != nil {
 		return err
 	}
+	return s.PostStreamWithOptionsContext(ctx, bytes.NewReader(body), "application/json", opts)
+}
 
-	if response.Header.Status != 0 {
-		errorMsg := fmt.Sprintf("Solr returned status %d", response.Header.Status)
-		return errors.New(errorMsg)
-	}
+// Commit issues a hard commit to Solr.
+func (s Solr) Commit() error {
+	return s.CommitContext(context.Background())
+}
+
+// CommitContext is like Commit but uses the provided context for
+// the HTTP request to Solr.
+func (s Solr) CommitContext(ctx context.Context) error {
+	return s.updateCommand(ctx, `{"commit":{}}`)
+}
+
+// SoftCommit issues a soft commit to Solr (makes the changes visible
+// to searches without flushing them to stable storage).
+func (s Solr) SoftCommit() error {
+	return s.SoftCommitContext(context.Background())
+}
 
-	return nil
+// SoftCommitContext is like SoftCommit but uses the provided context for
+// the HTTP request to Solr.
+func (s Solr) SoftCommitContext(ctx context.Context) error {
+	return s.updateCommand(ctx, `{"commit":{"softCommit":true}}`)
+}
+
+// Optimize issues an optimize (i.e. a hard commit and a forced
+// merge of the index segments) to Solr.
+func (s Solr) Optimize() error {
+	return s.OptimizeContext(context.Background())
+}
+
+// OptimizeContext is like Optimize but uses the provided context for
+// the HTTP request to Solr.
+func (s Solr) OptimizeContext(ctx context.Context) error {
+	return s.updateCommand(ctx, `{"optimize":{}}`)
+}
+
+// Rollback discards all the changes sent to Solr since the last commit.
+func (s Solr) Rollback() error {
+	return s.RollbackContext(context.Background())
+}
+
+// RollbackContext is like Rollback but uses the provided context for
+// the HTTP request to Solr.
+func (s Solr) RollbackContext(ctx context.Context) error {
+	return s.updateCommand(ctx, `{"rollback":{}}`)
+}
+
+func (s Solr) updateCommand(ctx context.Context, command string) error {
+	return s.PostStringWithOptionsContext(ctx, command, UpdateOptions{})
 }
 
 // Issues a search for the text indicated. Uses the server's default
 // values for all other Solr parameters.
 func (s Solr) SearchText(text string) (SearchResponse, error) {
+	return s.SearchTextContext(context.Background(), text)
+}
+
+// SearchTextContext is like SearchText but uses the provided context for
+// the HTTP request to Solr.
+func (s Solr) SearchTextContext(ctx context.Context, text string) (SearchResponse, error) {
 	options := map[string]string{}
 	facets := map[string]string{}
 	params := NewSearchParams(text, options, facets)
-	return s.Search(params)
+	return s.SearchContext(ctx, params)
 }
 
-func (s Solr) httpGet(url string) (responseRaw, error) {
+func (s Solr) httpGet(ctx context.Context, url string) (responseRaw, error) {
 	s.log("Solr HTTP GET", url)
-	r, err := http.Get(url)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return responseRaw{}, err
+	}
+
+	r, err := s.do(req)
+	if err != nil {
+		return responseRaw{}, contextError(ctx, err)
+	}
+
+	return readResponse(url, r)
+}
+
+func (s Solr) httpPost(ctx context.Context, url, contentType string, body io.Reader) (responseRaw, error) {
+	s.log("Solr HTTP POST", url)
+	req, err := http.NewRequestWithContext(ctx, "POST", url, body)
 	if err != nil {
 		return responseRaw{}, err
 	}
+	req.Header.Set("Content-Type", contentType)
+
+	r, err := s.do(req)
+	if err != nil {
+		return responseRaw{}, contextError(ctx, err)
+	}
+	return readResponse(url, r)
+}
 
+// readResponse reads and parses the response from Solr. It returns
+// a *SolrError if Solr reported an error either via the HTTP status
+// code or in the body of the response.
+func readResponse(url string, r *http.Response) (responseRaw, error) {
 	defer r.Body.Close()
 	body, err := ioutil.ReadAll(r.Body)
 	if err != nil {
@@ -231,42 +571,50 @@ func (s Solr) httpGet(url string) (responseRaw, error) {
 	}
 
 	if r.StatusCode < 200 || r.StatusCode > 299 {
-		msg := fmt.Sprintf("HTTP Status: %s. ", r.Status)
-		if len(body) > 0 {
-			msg += fmt.Sprintf("Body: %s", body)
-		}
-		return responseRaw{}, errors.New(msg)
+		return responseRaw{}, newSolrError(url, r.StatusCode, body)
 	}
 
 	response, err := NewResponseRaw([]byte(body))
 	if err == nil {
 		// HTTP request was successful but Solr reported an error.
-		if response.Error.Trace != "" {
-			msg := fmt.Sprintf("Solr Error. %#v", response.Error)
-			err = errors.New(msg)
+		if response.Header.Status != 0 || !response.Error.isEmpty() {
+			err = newSolrError(url, r.StatusCode, body)
 		}
 	} else {
+		e := newSolrError(url, r.StatusCode, body)
+		e.Msg = err.Error()
 		if len(r.Header["Content-Type"]) > 0 {
 			// Perhaps the response was not in JSON
 			// (e.g. if Solr returns XML by default)
-			msg := fmt.Sprintf("%s. Solr's response Content-Type: %s", err, r.Header["Content-Type"])
-			err = errors.New(msg)
+			e.Msg = fmt.Sprintf("%s. Solr's response Content-Type: %s", err, r.Header["Content-Type"])
 		}
+		err = e
 	}
 	return response, err
 }
 
-func (s Solr) httpPost(url, contentType, body string) (string, error) {
-	s.log("Solr HTTP POST", url)
-	payload := bytes.NewBufferString(body)
-	r, err := http.Post(url, contentType, payload)
-	if err != nil {
-		return "", err
+// do sends the HTTP request to Solr with the configured client
+// and headers.
+func (s Solr) do(req *http.Request) (*http.Response, error) {
+	if s.userAgent != "" {
+		req.Header.Set("User-Agent", s.userAgent)
+	}
+	if s.credentials != nil {
+		if err := s.credentials.Apply(req); err != nil {
+			return nil, err
+		}
 	}
+	return s.client().Do(req)
+}
 
-	defer r.Body.Close()
-	respStr, err := ioutil.ReadAll(r.Body)
-	return string(respStr), nil
+// contextError returns the context's error (e.g. context.Canceled or
+// context.DeadlineExceeded) rather than the *url.Error wrapping it
+// when the HTTP request failed because the context was done.
+func contextError(ctx context.Context, err error) error {
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return ctxErr
+	}
+	return err
 }
 
 func (s Solr) log(msg1, msg2 string) {
@@ -1,9 +1,14 @@
 package solr
 
 import (
+	"context"
+	"net/http"
+	"net/http/httptest"
 	"net/url"
 	"strings"
 	"testing"
+
+	"github.com/hectorcorrea/solr/query"
 )
 
 func TestGetParamsUrl(t *testing.T) {
@@ -64,3 +69,162 @@ func TestFacets(t *testing.T) {
 		t.Errorf("Invalid facet AddURL: %s", facets[0].Values[0].AddUrl)
 	}
 }
+
+func TestSearchContextCanceled(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	solr := New(server.URL, false)
+	_, err := solr.SearchTextContext(ctx, "hello")
+	if err != context.Canceled {
+		t.Errorf("Expected context.Canceled, got: %v", err)
+	}
+}
+
+func TestUpdateOptionsUrl(t *testing.T) {
+	qs := DefaultUpdateOptions().toQueryString()
+	if qs != "wt=json&commit=true&" {
+		t.Errorf("Unexpected UpdateOptions URL: %s", qs)
+	}
+
+	opts := UpdateOptions{CommitWithin: 5000, Overwrite: Bool(false)}
+	qs = opts.toQueryString()
+	if qs != "wt=json&commitWithin=5000&overwrite=false&" {
+		t.Errorf("Unexpected UpdateOptions URL: %s", qs)
+	}
+}
+
+func TestFacetsFromResponse(t *testing.T) {
+	raw, err := NewResponseRaw([]byte(`{"response":{"numFound":3,"start":0,"docs":[]},
+		"facet_counts":{"facet_fields":{"subject":["geography",2,"history",1]}}}`))
+	if err != nil {
+		t.Fatalf("NewResponseRaw error: %s", err)
+	}
+
+	qs := url.Values{"fq": []string{"subject|history"}}
+	params := NewSearchParamsFromQs(qs, map[string]string{}, map[string]string{"subject": "Subject"})
+	r := newSearchResponse(params, raw)
+	values := r.Facets[0].Values
+	if len(values) != 2 || values[0].Count != 2 || values[1].Count != 1 ||
+		values[0].Active || !values[1].Active {
+		t.Errorf("Unexpected facet values: %#v", values)
+	}
+}
+
+func TestFilterQueryEscaping(t *testing.T) {
+	fqs := newFilterQueries([]string{`f1|say "hi" \o/`})
+	qs := fqs.toQueryString(Facets{})
+	if qs != "fq=f1:%22say+%5C%22hi%5C%22+%5C%5Co%2F%22&" {
+		t.Errorf("Unexpected filter query: %s", qs)
+	}
+
+	fqs = newFilterQueries([]string{"title:x OR *|y", "-title|y", "year_i|2000"})
+	if len(fqs) != 1 || fqs[0].Field != "year_i" {
+		t.Errorf("Unexpected filter queries: %#v", fqs)
+	}
+
+	params := NewSearchParams("", map[string]string{}, map[string]string{})
+	params.Query = query.Term("title", "a:b")
+	if qs := params.toSolrQueryString(); qs != "q=title%3Aa%5C%3Ab&" {
+		t.Errorf("Unexpected SearchParams URL: %s", qs)
+	}
+}
+
+func TestSearchResponseUrlWithQuery(t *testing.T) {
+	params := NewSearchParams("", map[string]string{}, map[string]string{})
+	params.Query = query.Term("title", "rome")
+	params.Rows = 10
+	r := newSearchResponse(params, responseRaw{})
+	if r.Q != "title:rome" {
+		t.Errorf("Unexpected Q: %s", r.Q)
+	}
+	if r.Url != "q=title%3Arome&" || r.UrlNoQ != "" {
+		t.Errorf("Unexpected URLs: %s / %s", r.Url, r.UrlNoQ)
+	}
+	if r.NextPageUrl != "q=title%3Arome&start=10&" {
+		t.Errorf("Unexpected NextPageUrl: %s", r.NextPageUrl)
+	}
+}
+
+func TestSearchResponseUrlRoundTrip(t *testing.T) {
+	params := NewSearchParams("", map[string]string{}, map[string]string{})
+	params.Query = query.Bool().
+		Must(query.Term("title", "a&b")).
+		Must(query.Phrase("subject", "x y"))
+	r := newSearchResponse(params, responseRaw{})
+
+	qs, err := url.ParseQuery(r.Url)
+	if err != nil {
+		t.Fatalf("ParseQuery error: %s", err)
+	}
+	parsed := NewSearchParamsFromQs(qs, map[string]string{}, map[string]string{})
+	if parsed.Q != params.Query.String() {
+		t.Errorf("Query did not survive the round trip: %s != %s", parsed.Q, params.Query.String())
+	}
+}
+
+func TestSort(t *testing.T) {
+	clientQs := url.Values{
+		"q":    []string{"hello"},
+		"sort": []string{"sum(a_i,b_i) desc,title_s asc"},
+	}
+	params := NewSearchParamsFromQs(clientQs, map[string]string{}, map[string]string{})
+	if len(params.Sort) != 2 || params.Sort[0] != Desc("sum(a_i,b_i)") || params.Sort[1] != Asc("title_s") {
+		t.Errorf("Unexpected sort: %#v", params.Sort)
+	}
+
+	qs := params.toSolrQueryString()
+	if qs != "q=hello&sort=sum%28a_i%2Cb_i%29+desc%2Ctitle_s+asc&" {
+		t.Errorf("Unexpected SearchParams URL: %s", qs)
+	}
+
+	params.Sort = ParseSort("year_i desc")
+	params.SortOptions = []SortOption{
+		NewSortOption("Relevance", "score desc"),
+		NewSortOption("Newest first", "year_i desc"),
+	}
+	r := newSearchResponse(params, responseRaw{})
+	if r.NextPageUrl != "q=hello&sort=year_i+desc&start=10&" {
+		t.Errorf("Unexpected NextPageUrl: %s", r.NextPageUrl)
+	}
+
+	if r.SortOptions[0].Url != "q=hello&sort=score+desc&" || r.SortOptions[0].Active || !r.SortOptions[1].Active {
+		t.Errorf("Unexpected sort options: %#v", r.SortOptions)
+	}
+}
+
+func TestMultiSelectFacets(t *testing.T) {
+	raw, err := NewResponseRaw([]byte(`{"response":{"numFound":3,"start":0,"docs":[]},
+		"facet_counts":{"facet_fields":{"format":["Book",5,"Map",2,"Video",1]}}}`))
+	if err != nil {
+		t.Fatalf("NewResponseRaw error: %s", err)
+	}
+
+	qs := url.Values{"fq": []string{"format|Book", "subject|history", "format|Map"}}
+	params := NewSearchParamsFromQs(qs, map[string]string{}, map[string]string{})
+	params.Facets = Facets{FacetField{Field: "format", Title: "Format", MultiSelect: true}}
+
+	s := params.toSolrQueryString()
+	expected := "q=%2A&" +
+		"fq=%7B%21tag%3Dformat%7Dformat%3A%22Book%22+OR+format%3A%22Map%22&" +
+		"fq=subject:%22history%22&" +
+		"facet=on&facet.field=%7B%21ex%3Dformat%7Dformat&"
+	if s != expected {
+		t.Errorf("Unexpected SearchParams URL: %s", s)
+	}
+
+	r := newSearchResponse(params, raw)
+	values := r.Facets[0].Values
+	if !values[0].Active || !values[1].Active || values[2].Active {
+		t.Errorf("Unexpected facet values: %#v", values)
+	}
+
+	r.Facets.SetAddRemoveUrls(r.Url)
+	if values[1].RemoveUrl != "q=%2A&fq=format|Book&" {
+		t.Errorf("Unexpected RemoveUrl: %s", values[1].RemoveUrl)
+	}
+}
@@ -0,0 +1,108 @@
+package solr
+
+import "strings"
+
+// SortClause represents one of the criteria to sort the results of
+// a search by. Field can be the name of a field or a function (e.g.
+// "geodist()" or "sum(x_i,y_i)").
+type SortClause struct {
+	Field string
+	Desc  bool
+}
+
+// SortOption represents a way to sort the results that can be offered
+// to the user (e.g. "Newest first"). When returned in SearchResponse
+// it includes the URL to execute the search with this sort.
+type SortOption struct {
+	Title  string
+	Sort   []SortClause
+	Url    string // URL to execute the search with this sort
+	Active bool   // true if the search is sorted this way
+}
+
+// Asc creates a SortClause to sort ascending by a field.
+func Asc(field string) SortClause {
+	return SortClause{Field: field}
+}
+
+// Desc creates a SortClause to sort descending by a field.
+func Desc(field string) SortClause {
+	return SortClause{Field: field, Desc: true}
+}
+
+func (c SortClause) String() string {
+	if c.Desc {
+		return c.Field + " desc"
+	}
+	return c.Field + " asc"
+}
+
+// NewSortOption creates a SortOption from a sort string as used in
+// Solr's sort parameter, e.g. NewSortOption("Newest first", "year_i desc").
+func NewSortOption(title, sort string) SortOption {
+	return SortOption{Title: title, Sort: ParseSort(sort)}
+}
+
+// ParseSort parses a string in the format of Solr's sort parameter,
+// e.g. "score desc,title_s asc", into SortClauses. Commas inside of
+// functions (e.g. "sum(x_i,y_i) desc") are handled.
+func ParseSort(sort string) []SortClause {
+	clauses := []SortClause{}
+	for _, token := range splitSort(sort) {
+		token = strings.TrimSpace(token)
+		if token == "" {
+			continue
+		}
+		clause := SortClause{Field: token}
+		if i := strings.LastIndexAny(token, " \t"); i != -1 {
+			direction := strings.ToLower(token[i+1:])
+			if direction == "asc" || direction == "desc" {
+				clause.Field = strings.TrimSpace(token[:i])
+				clause.Desc = direction == "desc"
+			}
+		}
+		clauses = append(clauses, clause)
+	}
+	return clauses
+}
+
+// splitSort splits the sort string on the commas that are not
+// inside of parenthesis.
+func splitSort(sort string) []string {
+	tokens := []string{}
+	depth, start := 0, 0
+	for i, r := range sort {
+		switch r {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				tokens = append(tokens, sort[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(tokens, sort[start:])
+}
+
+func sortToString(clauses []SortClause) string {
+	tokens := []string{}
+	for _, clause := range clauses {
+		tokens = append(tokens, clause.String())
+	}
+	return strings.Join(tokens, ",")
+}
+
+func sortEqual(a, b []SortClause) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
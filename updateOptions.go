@@ -0,0 +1,54 @@
+package solr
+
+import "strconv"
+
+// UpdateOptions represents the parameters passed to Solr's /update
+// handler when adding or deleting documents.
+//
+// The zero value does not request a commit, documents will become
+// visible when Solr's autoCommit/autoSoftCommit kicks in (or when
+// Commit() or SoftCommit() are called explicitly).
+type UpdateOptions struct {
+	Commit         bool  // Issue a hard commit after the update.
+	SoftCommit     bool  // Issue a soft commit after the update.
+	CommitWithin   int   // Commit within this many milliseconds (0 to not send it).
+	Overwrite      *bool // Overwrite documents with the same id (nil uses Solr's default).
+	WaitSearcher   *bool // Wait for a new searcher on commit (nil uses Solr's default).
+	ExpungeDeletes bool  // Merge segments with deletes away on commit.
+}
+
+// DefaultUpdateOptions returns the options used by PostDocs(), Delete()
+// and the other update methods when no options are indicated. They
+// issue a hard commit on every update.
+func DefaultUpdateOptions() UpdateOptions {
+	return UpdateOptions{Commit: true}
+}
+
+// Bool returns a pointer to the value provided. Useful to set
+// the Overwrite and WaitSearcher options.
+func Bool(value bool) *bool {
+	return &value
+}
+
+func (opts UpdateOptions) toQueryString() string {
+	qs := qsAdd("wt", "json")
+	if opts.Commit {
+		qs += qsAdd("commit", "true")
+	}
+	if opts.SoftCommit {
+		qs += qsAdd("softCommit", "true")
+	}
+	if opts.CommitWithin > 0 {
+		qs += qsAddInt("commitWithin", opts.CommitWithin)
+	}
+	if opts.Overwrite != nil {
+		qs += qsAdd("overwrite", strconv.FormatBool(*opts.Overwrite))
+	}
+	if opts.WaitSearcher != nil {
+		qs += qsAdd("waitSearcher", strconv.FormatBool(*opts.WaitSearcher))
+	}
+	if opts.ExpungeDeletes {
+		qs += qsAdd("expungeDeletes", "true")
+	}
+	return qs
+}